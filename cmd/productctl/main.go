@@ -0,0 +1,69 @@
+// Command productctl runs admin tasks against the product service.
+//
+//	productctl [-addr localhost:8080] -token <authorization header> <command> [flags] [args]
+//
+// stock-count needs a supplier token, reindex an admin token.
+//
+// Commands:
+//
+//	stock-count  preview or apply a stock count CSV
+//	reindex      send every product to the search service
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"google.golang.org/grpc"
+)
+
+var commands = map[string]func(client pb.ProductServiceClient, args []string){
+	"stock-count": stockCount,
+	"reindex":     reindex,
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "product service address")
+	token := flag.String("token", os.Getenv("PRODUCTCTL_TOKEN"), "authorization header sent with every call, $PRODUCTCTL_TOKEN by default")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: productctl [-addr host:port] [-token token] stock-count|reindex [flags] [args]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	command, ok := commands[flag.Arg(0)]
+	if !ok {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	options := []grpc.DialOption{grpc.WithInsecure()}
+	if *token != "" {
+		options = append(options, grpc.WithPerRPCCredentials(authorization(*token)))
+	}
+	conn, err := grpc.Dial(*addr, options...)
+	if err != nil {
+		log.Fatal("can't dial product service: ", err)
+	}
+	defer conn.Close()
+
+	command(pb.NewProductServiceClient(conn), flag.Args()[1:])
+}
+
+// authorization sends the token as the authorization header of every call
+type authorization string
+
+func (token authorization) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": string(token)}, nil
+}
+
+func (authorization) RequireTransportSecurity() bool {
+	return false
+}
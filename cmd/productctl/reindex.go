@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+)
+
+// reindex sends every product to the search service, printing the progress. An interrupted
+// run continues from its last checkpoint with -resume and the reindex id it printed.
+//
+//	productctl -token "$ADMIN_TOKEN" reindex [-resume 3] [-batch 100] [-rate 50]
+func reindex(client pb.ProductServiceClient, args []string) {
+	flags := flag.NewFlagSet("reindex", flag.ExitOnError)
+	resume := flags.Int64("resume", 0, "reindex id to resume")
+	batch := flags.Int("batch", 0, "products per checkpoint, 100 by default")
+	rate := flags.Int("rate", 0, "products per second, 50 by default")
+	flags.Parse(args)
+
+	stream, err := client.ReindexSearch(context.Background(), &pb.ReindexSearchRequest{
+		ReindexId:     *resume,
+		BatchSize:     int32(*batch),
+		RatePerSecond: int32(*rate),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for {
+		progress, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("reindex %d: %d/%d products, last product %d\n", progress.GetReindexId(), progress.GetIndexed(), progress.GetTotal(), progress.GetLastProductId())
+		if progress.GetDone() {
+			fmt.Println("reindex finished")
+		}
+	}
+}
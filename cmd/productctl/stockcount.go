@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+)
+
+// stockCount sends a stock count CSV of the supplier owning the token, previewing the
+// differences with the current stock or applying them with -apply. The CSV needs a header
+// with product_id and quantity columns, location_id is optional.
+//
+//	productctl -token "$TOKEN" stock-count [-apply] [-note "count 2023-05"] count.csv
+func stockCount(client pb.ProductServiceClient, args []string) {
+	flags := flag.NewFlagSet("stock-count", flag.ExitOnError)
+	apply := flags.Bool("apply", false, "apply the differences instead of only previewing them")
+	note := flags.String("note", "", "note recorded with every adjustment")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	resp, err := client.ImportStockCount(ctx, &pb.ImportStockCountRequest{
+		Csv:   data,
+		Apply: *apply,
+		Note:  *note,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(resp.GetListError()) > 0 {
+		for _, rowErr := range resp.GetListError() {
+			fmt.Fprintf(os.Stderr, "row %d: %s\n", rowErr.GetRow(), rowErr.GetMessage())
+		}
+		os.Exit(1)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tPRODUCT\tLOCATION\tCURRENT\tCOUNTED\tDELTA")
+	for _, line := range resp.GetListLine() {
+		if line.GetDelta() == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t%+d\n", line.GetRow(), line.GetProductId(), line.GetLocationId(), line.GetCurrent(), line.GetCounted(), line.GetDelta())
+	}
+	w.Flush()
+
+	if resp.GetApplied() {
+		fmt.Println("stock count applied")
+	} else {
+		fmt.Println("preview only, run with -apply to record the differences")
+	}
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS customer_activity (
+    "customer_id" bigint NOT NULL,
+    "product_id" bigint NOT NULL,
+    "activity_type" varchar(16) NOT NULL,
+    "hit" integer NOT NULL DEFAULT 1,
+    "updated_at" timestamptz NOT NULL DEFAULT (now()),
+    PRIMARY KEY ("customer_id", "product_id", "activity_type")
+);
+
+ALTER TABLE customer_activity
+ADD
+    FOREIGN KEY ("product_id") REFERENCES product ("id") ON DELETE CASCADE;
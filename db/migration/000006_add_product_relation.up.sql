@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS product_relation (
+    "product_id" bigint NOT NULL,
+    "related_product_id" bigint NOT NULL,
+    "relation_type" varchar(32) NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    PRIMARY KEY (
+        "product_id",
+        "related_product_id",
+        "relation_type"
+    ),
+    CHECK ("product_id" <> "related_product_id")
+);
+
+ALTER TABLE product_relation
+ADD
+    FOREIGN KEY ("product_id") REFERENCES product ("id") ON DELETE CASCADE;
+
+ALTER TABLE product_relation
+ADD
+    FOREIGN KEY ("related_product_id") REFERENCES product ("id") ON DELETE CASCADE;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS product_text_vector (
+    "product_id" bigint PRIMARY KEY,
+    "terms" jsonb NOT NULL,
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE product_text_vector
+ADD
+    FOREIGN KEY ("product_id") REFERENCES product ("id") ON DELETE CASCADE;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS inventory_reservation (
+    "id" serial8 PRIMARY KEY,
+    "product_id" bigint NOT NULL,
+    "quantity" integer NOT NULL CHECK ("quantity" > 0),
+    "status" varchar(16) NOT NULL DEFAULT 'active',
+    "expires_at" timestamptz NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE inventory_reservation
+ADD
+    FOREIGN KEY ("product_id") REFERENCES product ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON inventory_reservation ("product_id")
+WHERE "status" = 'active';
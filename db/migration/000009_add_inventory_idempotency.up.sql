@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS inventory_idempotency (
+    "key" varchar(128) PRIMARY KEY,
+    "method" varchar(64) NOT NULL,
+    "response" bytea,
+    "error_status" bytea,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON inventory_idempotency ("created_at");
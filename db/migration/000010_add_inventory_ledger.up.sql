@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS inventory_ledger (
+    "id" serial8 PRIMARY KEY,
+    "product_id" bigint NOT NULL,
+    "delta" integer NOT NULL,
+    "balance" integer NOT NULL,
+    "reason" varchar(32) NOT NULL,
+    "actor" varchar(64) NOT NULL,
+    "reference" varchar(128) NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON inventory_ledger ("product_id", "id");
+
+-- the stock of existing products is the opening balance of their history
+INSERT INTO
+    inventory_ledger (
+        "product_id",
+        "delta",
+        "balance",
+        "reason",
+        "actor"
+    )
+SELECT
+    "id",
+    "inventory",
+    "inventory",
+    'opening_balance',
+    'migration'
+FROM product;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS product_stock;
+
+DROP TABLE IF EXISTS stock_location;
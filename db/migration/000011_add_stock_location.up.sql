@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS stock_location (
+    "id" serial8 PRIMARY KEY,
+    "supplier_id" bigint NOT NULL,
+    "name" varchar(128) NOT NULL,
+    "priority" integer NOT NULL DEFAULT 0,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON stock_location ("supplier_id");
+
+CREATE TABLE IF NOT EXISTS product_stock (
+    "product_id" bigint NOT NULL,
+    "location_id" bigint NOT NULL,
+    "quantity" integer NOT NULL DEFAULT 0 CHECK ("quantity" >= 0),
+    PRIMARY KEY ("product_id", "location_id")
+);
+
+ALTER TABLE product_stock
+ADD
+    FOREIGN KEY ("product_id") REFERENCES product ("id") ON DELETE CASCADE;
+
+ALTER TABLE product_stock
+ADD
+    FOREIGN KEY ("location_id") REFERENCES stock_location ("id") ON DELETE CASCADE;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS stock_alert;
+
+ALTER TABLE product
+DROP COLUMN "reorder_threshold";
@@ -0,0 +1,15 @@
+ALTER TABLE product
+ADD
+    COLUMN "reorder_threshold" integer NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS stock_alert (
+    "id" serial8 PRIMARY KEY,
+    "product_id" bigint NOT NULL,
+    "supplier_id" bigint NOT NULL,
+    "alert_type" varchar(16) NOT NULL,
+    "inventory" integer NOT NULL,
+    "threshold" integer NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON stock_alert ("supplier_id", "id");
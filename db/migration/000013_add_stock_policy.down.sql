@@ -0,0 +1,5 @@
+ALTER TABLE product
+DROP COLUMN "stock_policy",
+DROP COLUMN "backorder_limit",
+DROP COLUMN "preorder_until",
+DROP COLUMN "available_at";
@@ -0,0 +1,9 @@
+ALTER TABLE product
+ADD
+    COLUMN "stock_policy" varchar(16) NOT NULL DEFAULT 'deny',
+ADD
+    COLUMN "backorder_limit" integer NOT NULL DEFAULT 0,
+ADD
+    COLUMN "preorder_until" timestamptz,
+ADD
+    COLUMN "available_at" timestamptz;
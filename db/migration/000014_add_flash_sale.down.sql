@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS flash_sale_purchase;
+
+DROP TABLE IF EXISTS flash_sale;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS flash_sale (
+    "id" serial8 PRIMARY KEY,
+    "product_id" bigint NOT NULL,
+    "supplier_id" bigint NOT NULL,
+    "sale_price" bigint NOT NULL,
+    "starts_at" timestamptz NOT NULL,
+    "ends_at" timestamptz NOT NULL,
+    "allocated" integer NOT NULL,
+    "remaining" integer NOT NULL CHECK ("remaining" >= 0),
+    "per_customer_limit" integer NOT NULL,
+    "location_id" bigint NOT NULL DEFAULT 0,
+    "status" varchar(16) NOT NULL DEFAULT 'active',
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON flash_sale ("product_id", "ends_at");
+
+CREATE INDEX ON flash_sale ("status", "ends_at");
+
+CREATE TABLE IF NOT EXISTS flash_sale_purchase (
+    "flash_sale_id" bigint NOT NULL,
+    "customer_id" bigint NOT NULL,
+    "quantity" integer NOT NULL,
+    PRIMARY KEY ("flash_sale_id", "customer_id")
+);
+
+ALTER TABLE flash_sale
+ADD
+    FOREIGN KEY ("product_id") REFERENCES product ("id") ON DELETE CASCADE;
+
+ALTER TABLE flash_sale_purchase
+ADD
+    FOREIGN KEY ("flash_sale_id") REFERENCES flash_sale ("id") ON DELETE CASCADE;
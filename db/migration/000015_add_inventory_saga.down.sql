@@ -0,0 +1,4 @@
+ALTER TABLE inventory_reservation
+DROP COLUMN "order_id";
+
+DROP TABLE IF EXISTS inventory_saga;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS inventory_saga (
+    "order_id" bigint PRIMARY KEY,
+    "status" varchar(16) NOT NULL,
+    "expires_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE inventory_reservation
+ADD
+    COLUMN "order_id" bigint;
+
+CREATE INDEX ON inventory_reservation ("order_id")
+WHERE "order_id" IS NOT NULL;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS search_outbox (
+    "id" serial8 PRIMARY KEY,
+    "product_id" bigint NOT NULL,
+    "operation" varchar(16) NOT NULL,
+    "attempts" integer NOT NULL DEFAULT 0,
+    "last_error" text NOT NULL DEFAULT '',
+    "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON search_outbox ("next_attempt_at");
+
+-- products written before the outbox existed may be missing from the search index
+INSERT INTO
+    search_outbox (product_id, operation)
+SELECT id, 'upsert'
+FROM product;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS search_reindex (
+    "id" serial8 PRIMARY KEY,
+    "last_product_id" bigint NOT NULL DEFAULT 0,
+    "indexed" bigint NOT NULL DEFAULT 0,
+    "status" varchar(16) NOT NULL DEFAULT 'running',
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
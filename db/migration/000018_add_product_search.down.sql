@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS product_search_refresh ON product;
+
+DROP FUNCTION IF EXISTS product_search_refresh;
+
+DROP FUNCTION IF EXISTS product_search_document;
+
+DROP TABLE IF EXISTS product_search;
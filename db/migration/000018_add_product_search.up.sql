@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS product_search (
+    "product_id" bigint PRIMARY KEY,
+    "document" tsvector NOT NULL
+);
+
+ALTER TABLE product_search
+ADD
+    FOREIGN KEY ("product_id") REFERENCES product ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON product_search USING gin ("document");
+
+CREATE OR REPLACE FUNCTION product_search_document(
+    name text,
+    brand text,
+    description text
+) RETURNS tsvector AS $$
+    SELECT
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(brand, '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'C');
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION product_search_refresh() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO product_search (product_id, document)
+    VALUES (NEW.id, product_search_document(NEW.name, NEW.brand, NEW.description))
+    ON CONFLICT (product_id) DO UPDATE SET document = EXCLUDED.document;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER product_search_refresh
+AFTER INSERT OR UPDATE OF name, brand, description ON product
+FOR EACH ROW EXECUTE FUNCTION product_search_refresh();
+
+INSERT INTO
+    product_search (product_id, document)
+SELECT
+    id,
+    product_search_document(name, brand, description)
+FROM product;
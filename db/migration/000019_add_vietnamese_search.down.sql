@@ -0,0 +1,30 @@
+CREATE OR REPLACE FUNCTION product_search_refresh() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO product_search (product_id, document)
+    VALUES (NEW.id, product_search_document(NEW.name, NEW.brand, NEW.description))
+    ON CONFLICT (product_id) DO UPDATE SET document = EXCLUDED.document;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE product_search
+DROP COLUMN "folded";
+
+CREATE OR REPLACE FUNCTION product_search_document(
+    name text,
+    brand text,
+    description text
+) RETURNS tsvector AS $$
+    SELECT
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(brand, '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'C');
+$$ LANGUAGE sql IMMUTABLE;
+
+UPDATE product_search
+SET
+    document = product_search_document(product.name, product.brand, product.description)
+FROM product
+WHERE product.id = product_search.product_id;
+
+DROP FUNCTION IF EXISTS fold_vietnamese;
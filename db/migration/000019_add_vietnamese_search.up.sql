@@ -0,0 +1,46 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- fold_vietnamese lowercases and strips the diacritics, "Điện thoại" becomes "dien thoai".
+-- unaccent is only stable because its dictionary can change, naming it makes the fold usable in indexes.
+CREATE OR REPLACE FUNCTION fold_vietnamese(value text) RETURNS text AS $$
+    SELECT lower(unaccent('unaccent', translate(coalesce(value, ''), 'đĐ', 'dD')));
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION product_search_document(
+    name text,
+    brand text,
+    description text
+) RETURNS tsvector AS $$
+    SELECT
+        setweight(to_tsvector('simple', fold_vietnamese(name)), 'A') ||
+        setweight(to_tsvector('simple', fold_vietnamese(brand)), 'B') ||
+        setweight(to_tsvector('simple', fold_vietnamese(description)), 'C');
+$$ LANGUAGE sql IMMUTABLE;
+
+ALTER TABLE product_search
+ADD
+    COLUMN "folded" text NOT NULL DEFAULT '';
+
+CREATE INDEX ON product_search USING gin ("folded" gin_trgm_ops);
+
+CREATE OR REPLACE FUNCTION product_search_refresh() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO product_search (product_id, document, folded)
+    VALUES (
+        NEW.id,
+        product_search_document(NEW.name, NEW.brand, NEW.description),
+        fold_vietnamese(NEW.name || ' ' || coalesce(NEW.brand, ''))
+    )
+    ON CONFLICT (product_id) DO UPDATE SET document = EXCLUDED.document, folded = EXCLUDED.folded;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE product_search
+SET
+    document = product_search_document(product.name, product.brand, product.description),
+    folded = fold_vietnamese(product.name || ' ' || coalesce(product.brand, ''))
+FROM product
+WHERE product.id = product_search.product_id;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS product_event (
+    "id" serial8 PRIMARY KEY,
+    "product_id" bigint NOT NULL,
+    "event_type" varchar(32) NOT NULL,
+    "payload" bytea NOT NULL,
+    "published_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON product_event ("id") WHERE "published_at" IS NULL;
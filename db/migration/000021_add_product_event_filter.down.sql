@@ -0,0 +1,3 @@
+ALTER TABLE product_event
+DROP COLUMN IF EXISTS "supplier_id",
+DROP COLUMN IF EXISTS "category_id";
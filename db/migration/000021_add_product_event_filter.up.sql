@@ -0,0 +1,10 @@
+ALTER TABLE product_event
+ADD COLUMN IF NOT EXISTS "supplier_id" bigint NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS "category_id" bigint NOT NULL DEFAULT 0;
+
+UPDATE product_event
+SET
+    supplier_id = product.supplier_id,
+    category_id = product.category_id
+FROM product
+WHERE product.id = product_event.product_id;
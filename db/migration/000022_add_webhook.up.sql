@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS webhook_subscription (
+    "id" serial8 PRIMARY KEY,
+    "supplier_id" bigint NOT NULL,
+    "url" text NOT NULL,
+    "event_types" varchar(32) [] NOT NULL,
+    "secret" text NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON webhook_subscription ("supplier_id");
+
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+    "id" serial8 PRIMARY KEY,
+    "subscription_id" bigint NOT NULL REFERENCES webhook_subscription ("id") ON DELETE CASCADE,
+    "event_type" varchar(32) NOT NULL,
+    "payload" text NOT NULL,
+    "status" varchar(16) NOT NULL DEFAULT 'pending',
+    "attempts" integer NOT NULL DEFAULT 0,
+    "response_status" integer NOT NULL DEFAULT 0,
+    "last_error" text NOT NULL DEFAULT '',
+    "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+    "delivered_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON webhook_delivery ("next_attempt_at") WHERE "status" = 'pending';
+
+CREATE INDEX ON webhook_delivery ("subscription_id", "status");
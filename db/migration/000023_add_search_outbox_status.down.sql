@@ -0,0 +1 @@
+ALTER TABLE search_outbox DROP COLUMN IF EXISTS "status";
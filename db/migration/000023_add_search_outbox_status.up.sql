@@ -0,0 +1,7 @@
+ALTER TABLE search_outbox
+ADD COLUMN IF NOT EXISTS "status" varchar(16) NOT NULL DEFAULT 'pending';
+
+-- search-service can't remove products, results of deleted ones are dropped when hydrated
+DELETE FROM search_outbox WHERE "operation" = 'delete';
+
+CREATE INDEX ON search_outbox ("next_attempt_at") WHERE "status" = 'pending';
@@ -0,0 +1,5 @@
+ALTER TABLE product_event
+DROP COLUMN IF EXISTS "txid",
+DROP COLUMN IF EXISTS "sequence";
+
+CREATE INDEX ON product_event ("id") WHERE "published_at" IS NULL;
@@ -0,0 +1,15 @@
+-- sequences are given by the relay in commit order, events are stamped with their transaction
+-- instead of holding a lock until they commit
+ALTER TABLE product_event
+ADD COLUMN IF NOT EXISTS "txid" bigint NOT NULL DEFAULT (pg_current_xact_id()::text::bigint),
+ADD COLUMN IF NOT EXISTS "sequence" bigint;
+
+UPDATE product_event SET "sequence" = "id";
+
+DROP INDEX IF EXISTS product_event_id_idx;
+
+CREATE UNIQUE INDEX ON product_event ("sequence");
+
+CREATE INDEX ON product_event ("sequence") WHERE "published_at" IS NULL;
+
+CREATE INDEX ON product_event ("txid", "id") WHERE "sequence" IS NULL;
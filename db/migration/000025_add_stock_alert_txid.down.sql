@@ -0,0 +1,3 @@
+ALTER TABLE stock_alert DROP COLUMN IF EXISTS "txid";
+
+CREATE INDEX ON stock_alert ("supplier_id", "id");
@@ -0,0 +1,7 @@
+-- alerts are streamed in the order of their transaction, ids are given before commit
+ALTER TABLE stock_alert
+ADD COLUMN IF NOT EXISTS "txid" bigint NOT NULL DEFAULT (pg_current_xact_id()::text::bigint);
+
+DROP INDEX IF EXISTS stock_alert_supplier_id_id_idx;
+
+CREATE INDEX ON stock_alert ("supplier_id", "txid", "id");
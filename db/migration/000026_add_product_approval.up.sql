@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS product_approval (
+    "product_id" bigint PRIMARY KEY,
+    "approved_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE product_approval
+ADD
+    FOREIGN KEY ("product_id") REFERENCES product ("id") ON DELETE CASCADE;
@@ -0,0 +1 @@
+DELETE FROM search_outbox WHERE "operation" = 'delete';
@@ -0,0 +1,13 @@
+-- deletes dropped while search-service couldn't remove products are sent again
+INSERT INTO
+    search_outbox (product_id, operation)
+SELECT DISTINCT product_event.product_id, 'delete'
+FROM product_event
+WHERE
+    product_event.event_type = 'product_deleted'
+    AND NOT EXISTS (
+        SELECT 1
+        FROM product
+        WHERE
+            product.id = product_event.product_id
+    );
@@ -0,0 +1,18 @@
+-- stock held by active reservations, kept in the row that every decrement locks
+ALTER TABLE product
+ADD COLUMN IF NOT EXISTS "reserved" integer NOT NULL DEFAULT 0;
+
+UPDATE product
+SET reserved = held.quantity
+FROM (
+        SELECT
+            product_id,
+            sum(quantity) AS quantity
+        FROM inventory_reservation
+        WHERE status = 'active'
+        GROUP BY product_id
+    ) AS held
+WHERE product.id = held.product_id;
+
+CREATE INDEX ON inventory_reservation ("expires_at")
+WHERE "status" = 'active';
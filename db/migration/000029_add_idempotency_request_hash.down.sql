@@ -0,0 +1 @@
+ALTER TABLE inventory_idempotency DROP COLUMN IF EXISTS "request_hash";
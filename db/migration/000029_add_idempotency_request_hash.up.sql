@@ -0,0 +1,3 @@
+-- keys claimed before the hash existed are replayed without comparing the request
+ALTER TABLE inventory_idempotency
+ADD COLUMN IF NOT EXISTS "request_hash" bytea;
@@ -0,0 +1,6 @@
+UPDATE inventory_ledger
+SET
+    reference = left(note, 128)
+WHERE reason = 'adjustment';
+
+ALTER TABLE inventory_ledger DROP COLUMN IF EXISTS "note";
@@ -0,0 +1,9 @@
+ALTER TABLE inventory_ledger
+ADD COLUMN IF NOT EXISTS "note" text NOT NULL DEFAULT '';
+
+-- adjustments stored the supplier's note as their reference
+UPDATE inventory_ledger
+SET
+    note = reference,
+    reference = ''
+WHERE reason = 'adjustment';
@@ -0,0 +1,5 @@
+UPDATE inventory_ledger
+SET
+    reference = left(note, 128),
+    note = ''
+WHERE reason = 'stock_count';
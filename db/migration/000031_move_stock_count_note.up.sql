@@ -0,0 +1,6 @@
+-- stock counts stored the supplier's note as their reference
+UPDATE inventory_ledger
+SET
+    note = reference,
+    reference = ''
+WHERE reason = 'stock_count';
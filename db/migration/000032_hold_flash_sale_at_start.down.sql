@@ -0,0 +1,6 @@
+-- sales that never took their stock would give it back when they end
+UPDATE flash_sale
+SET status = 'ended'
+WHERE status = 'active' AND NOT held;
+
+ALTER TABLE flash_sale DROP COLUMN IF EXISTS "held";
@@ -0,0 +1,9 @@
+-- a sale takes its stock from the inventory when it starts, the sales created so far took it
+-- when they were created
+ALTER TABLE flash_sale
+ADD COLUMN IF NOT EXISTS "held" boolean NOT NULL DEFAULT false;
+
+UPDATE flash_sale SET held = true;
+
+CREATE INDEX ON flash_sale ("status", "starts_at")
+WHERE NOT "held";
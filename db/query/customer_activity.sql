@@ -0,0 +1,54 @@
+-- name: UpsertCustomerActivity :exec
+INSERT INTO
+    customer_activity (
+        customer_id,
+        product_id,
+        activity_type
+    )
+VALUES ($1, $2, $3) ON CONFLICT (
+        customer_id,
+        product_id,
+        activity_type
+    ) DO
+UPDATE
+SET
+    hit = customer_activity.hit + 1,
+    updated_at = now();
+
+-- name: GetCustomerProfile :many
+SELECT
+    customer_activity.product_id,
+    customer_activity.activity_type,
+    customer_activity.hit,
+    product.category_id,
+    product.brand,
+    product.price
+FROM customer_activity
+    JOIN product ON product.id = customer_activity.product_id
+WHERE customer_activity.customer_id = $1;
+
+-- name: GetRecommendCandidates :many
+SELECT *
+FROM product
+WHERE
+    inventory > 0
+    AND id NOT IN (
+        SELECT product_id
+        FROM customer_activity
+        WHERE
+            customer_activity.customer_id = @customer_id
+            AND activity_type = 'purchased'
+    )
+ORDER BY (
+        category_id = ANY(@category_ids :: bigint [])
+    ) DESC,
+    created_at DESC
+LIMIT @pool_size;
+
+-- name: GetProductPopularity :many
+SELECT
+    product_id,
+    coalesce(sum(hit) FILTER (WHERE activity_type = 'viewed'), 0)::bigint AS viewed,
+    coalesce(sum(hit) FILTER (WHERE activity_type = 'purchased'), 0)::bigint AS purchased
+FROM customer_activity
+GROUP BY product_id;
@@ -0,0 +1,107 @@
+-- name: CreateFlashSale :one
+INSERT INTO
+    flash_sale (
+        product_id,
+        supplier_id,
+        sale_price,
+        starts_at,
+        ends_at,
+        allocated,
+        remaining,
+        per_customer_limit,
+        location_id
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $6, $7, $8) RETURNING *;
+
+-- name: CountOverlappingFlashSales :one
+SELECT count(*)
+FROM flash_sale
+WHERE
+    product_id = $1
+    AND status = 'active'
+    AND starts_at < @ends_at
+    AND ends_at > @starts_at;
+
+-- name: GetFlashSale :one
+SELECT * FROM flash_sale WHERE id = $1;
+
+-- name: GetCurrentFlashSale :one
+SELECT *
+FROM flash_sale
+WHERE
+    product_id = $1
+    AND status = 'active'
+    AND remaining > 0
+    AND starts_at <= now()
+    AND ends_at > now()
+ORDER BY starts_at
+LIMIT 1;
+
+-- name: AddFlashSalePurchase :execrows
+INSERT INTO
+    flash_sale_purchase (
+        flash_sale_id,
+        customer_id,
+        quantity
+    )
+VALUES (@flash_sale_id, @customer_id, @quantity) ON CONFLICT (flash_sale_id, customer_id) DO
+UPDATE
+SET
+    quantity = flash_sale_purchase.quantity + EXCLUDED.quantity
+WHERE
+    flash_sale_purchase.quantity + EXCLUDED.quantity <= @per_customer_limit::integer;
+
+-- name: DescFlashSale :one
+UPDATE flash_sale
+SET
+    remaining = remaining - @count::integer
+WHERE
+    id = @id
+    AND status = 'active'
+    AND held
+    AND remaining >= @count::integer
+    AND starts_at <= now()
+    AND ends_at > now()
+RETURNING remaining;
+
+-- name: GetStartingFlashSales :many
+SELECT id
+FROM flash_sale
+WHERE
+    status = 'active'
+    AND NOT held
+    AND starts_at <= now()
+    AND ends_at > now()
+ORDER BY id
+LIMIT $1;
+
+-- name: StartFlashSale :one
+UPDATE flash_sale
+SET
+    held = true,
+    remaining = @remaining::integer
+WHERE
+    id = @id
+    AND status = 'active'
+    AND NOT held
+    AND starts_at <= now()
+    AND ends_at > now()
+RETURNING *;
+
+-- name: GetEndedFlashSales :many
+SELECT id
+FROM flash_sale
+WHERE
+    status = 'active'
+    AND ends_at <= now()
+ORDER BY id
+LIMIT $1;
+
+-- name: EndFlashSale :one
+UPDATE flash_sale
+SET status = 'ended'
+WHERE
+    id = $1
+    AND status = 'active'
+    AND ends_at <= now()
+RETURNING *;
@@ -0,0 +1,17 @@
+-- name: ClaimIdempotencyKey :execrows
+INSERT INTO
+    inventory_idempotency ("key", "method", "request_hash")
+VALUES ($1, $2, $3) ON CONFLICT DO NOTHING;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM inventory_idempotency WHERE "key" = $1;
+
+-- name: SaveIdempotencyResult :exec
+UPDATE inventory_idempotency
+SET
+    response = $2,
+    error_status = $3
+WHERE "key" = $1;
+
+-- name: DeleteIdempotencyKeysBefore :execrows
+DELETE FROM inventory_idempotency WHERE created_at < $1;
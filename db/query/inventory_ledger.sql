@@ -0,0 +1,43 @@
+-- name: InsertInventoryLedger :exec
+INSERT INTO
+    inventory_ledger (
+        product_id,
+        delta,
+        balance,
+        reason,
+        actor,
+        reference,
+        note
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetInventoryHistory :many
+SELECT *
+FROM inventory_ledger
+WHERE product_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: GetInventoryLedger :many
+SELECT * FROM inventory_ledger WHERE product_id = $1 ORDER BY id;
+
+-- name: GetInventoryMismatches :many
+SELECT
+    product.id,
+    product.inventory,
+    coalesce(sum(inventory_ledger.delta), 0)::integer AS ledger_balance
+FROM product
+    LEFT JOIN inventory_ledger ON inventory_ledger.product_id = product.id
+GROUP BY product.id
+HAVING
+    product.inventory <> coalesce(sum(inventory_ledger.delta), 0);
+
+-- name: AdjustInventory :one
+UPDATE product
+SET
+    inventory = inventory + @delta::integer
+WHERE
+    id = @id
+    AND inventory + @delta::integer >= 0
+RETURNING inventory;
@@ -0,0 +1,68 @@
+-- name: LockProductInventory :one
+SELECT inventory FROM product WHERE id = $1 FOR UPDATE;
+
+-- name: GetReservedInventory :one
+SELECT reserved FROM product WHERE id = $1;
+
+-- name: HoldReservedInventory :exec
+UPDATE product SET reserved = reserved + @quantity::integer WHERE id = @id;
+
+-- name: ReleaseReservedInventory :exec
+UPDATE product SET reserved = reserved - @quantity::integer WHERE id = @id;
+
+-- name: CreateReservation :one
+INSERT INTO
+    inventory_reservation (
+        product_id,
+        quantity,
+        expires_at
+    )
+VALUES ($1, $2, $3) RETURNING *;
+
+-- name: GetReservationForUpdate :one
+SELECT * FROM inventory_reservation WHERE id = $1 FOR UPDATE;
+
+-- name: UpdateReservationStatus :exec
+UPDATE inventory_reservation SET status = $2 WHERE id = $1;
+
+-- name: GetExpiredReservationProducts :many
+SELECT DISTINCT product_id
+FROM inventory_reservation
+WHERE
+    status = 'active'
+    AND expires_at <= now()
+LIMIT $1;
+
+-- name: ExpireProductReservations :many
+UPDATE inventory_reservation
+SET status = 'expired'
+WHERE
+    product_id = $1
+    AND status = 'active'
+    AND expires_at <= now()
+RETURNING quantity;
+
+-- name: LockProductsInventory :many
+SELECT
+    id,
+    inventory,
+    reserved,
+    stock_policy,
+    backorder_limit,
+    preorder_until
+FROM product
+WHERE id = ANY(@ids::bigint[])
+ORDER BY id
+FOR UPDATE;
+
+-- name: GetInventoryAvailability :many
+SELECT
+    id,
+    inventory,
+    stock_policy,
+    backorder_limit,
+    preorder_until,
+    reserved
+FROM product
+WHERE id = ANY(@ids::bigint[])
+ORDER BY id;
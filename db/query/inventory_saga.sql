@@ -0,0 +1,35 @@
+-- name: ClaimInventorySaga :execrows
+INSERT INTO
+    inventory_saga (order_id, status)
+VALUES ($1, $2) ON CONFLICT (order_id) DO NOTHING;
+
+-- name: GetInventorySagaForUpdate :one
+SELECT * FROM inventory_saga WHERE order_id = $1 FOR UPDATE;
+
+-- name: UpdateInventorySaga :exec
+UPDATE inventory_saga
+SET
+    status = $2,
+    expires_at = $3,
+    updated_at = now()
+WHERE order_id = $1;
+
+-- name: CreateOrderReservation :one
+INSERT INTO
+    inventory_reservation (
+        product_id,
+        quantity,
+        expires_at,
+        order_id
+    )
+VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: GetOrderReservationsForUpdate :many
+SELECT *
+FROM inventory_reservation
+WHERE order_id = $1
+ORDER BY product_id
+FOR UPDATE;
+
+-- name: DeleteOrderReservations :exec
+DELETE FROM inventory_reservation WHERE order_id = $1;
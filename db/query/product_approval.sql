@@ -0,0 +1,2 @@
+-- name: ApproveProduct :execrows
+INSERT INTO product_approval (product_id) VALUES ($1) ON CONFLICT DO NOTHING;
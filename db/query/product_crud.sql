@@ -71,7 +71,7 @@ SELECT * FROM product WHERE supplier_id = $1 ORDER BY price LIMIT $2 OFFSET $3;
 SELECT * FROM product WHERE supplier_id = $1 AND category_id=$4 ORDER BY price LIMIT $2 OFFSET $3;
 
 
--- name: UpdateProduct :exec
+-- name: UpdateProduct :execrows
 
 UPDATE product
 SET
@@ -85,24 +85,36 @@ WHERE id = $1 and supplier_id = $6;
 
 SELECT * FROM product WHERE id IN ($1);
 
--- name: DescInventory :exec
+-- name: DescInventory :one
 UPDATE product
 SET
     inventory = inventory - $1
-WHERE id = $2 and inventory >= $1;
+WHERE product.id = $2 and inventory - reserved + (
+        CASE
+            WHEN stock_policy = 'backorder' THEN backorder_limit
+            WHEN stock_policy = 'preorder'
+            AND preorder_until > now() THEN 2147483647
+            ELSE 0
+        END
+    ) >= $1
+RETURNING inventory;
 
 -- name: GetProductInventory :one
 SELECT inventory FROM product
 WHERE id = $1 LIMIT 1;
 
--- name: IncInventory :exec
+-- name: IncInventory :one
 UPDATE product
 SET
     inventory = inventory + $1
-WHERE id = $2;
+WHERE id = $2
+RETURNING inventory;
 
--- name: DeleteProduct :exec
+-- name: DeleteProduct :execrows
 DELETE FROM product WHERE id = $1 and supplier_id = $2;
 
--- name: DeleteProductByID :exec
-DELETE FROM product WHERE id = $1;
\ No newline at end of file
+-- name: DeleteProductByID :execrows
+DELETE FROM product WHERE id = $1;
+
+-- name: GetProductsByIDs :many
+SELECT * FROM product WHERE id = ANY(@ids::bigint[]);
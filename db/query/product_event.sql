@@ -0,0 +1,76 @@
+-- name: EnqueueProductEvent :exec
+INSERT INTO
+    product_event (
+        product_id,
+        supplier_id,
+        category_id,
+        event_type,
+        payload
+    )
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: TryLockProductEventRelay :one
+SELECT pg_try_advisory_xact_lock(hashtext('product_event_relay'))::bool AS locked;
+
+-- name: AssignProductEventSequences :execrows
+UPDATE product_event
+SET sequence = numbered.sequence
+FROM (
+        SELECT
+            id,
+            (
+                SELECT coalesce(max(sequence), 0)
+                FROM product_event
+            ) + row_number() OVER (
+                ORDER BY txid, id
+            ) AS sequence
+        FROM product_event
+        WHERE
+            sequence IS NULL
+            AND txid < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+    ) AS numbered
+WHERE product_event.id = numbered.id;
+
+-- name: GetUnpublishedProductEvents :many
+SELECT *
+FROM product_event
+WHERE published_at IS NULL AND sequence IS NOT NULL
+ORDER BY sequence
+LIMIT $1;
+
+-- name: MarkProductEventsPublished :exec
+UPDATE product_event
+SET published_at = now()
+WHERE sequence <= @last_sequence::bigint AND published_at IS NULL;
+
+-- name: GetProductEventLag :one
+SELECT
+    count(*) AS pending,
+    coalesce(
+        extract(
+            epoch
+            FROM
+                now() - min(created_at)
+        ),
+        0
+    )::float8 AS lag_seconds
+FROM product_event
+WHERE published_at IS NULL;
+
+-- name: GetLastProductEventSequence :one
+SELECT coalesce(max(sequence), 0)::bigint AS sequence FROM product_event;
+
+-- name: GetProductEventsAfter :many
+SELECT *
+FROM product_event
+WHERE
+    sequence > @after_sequence::bigint
+    AND sequence <= @last_sequence::bigint
+    AND (@supplier_id::bigint = 0 OR supplier_id = @supplier_id)
+    AND (@category_id::bigint = 0 OR category_id = @category_id)
+    AND (
+        coalesce(cardinality(@product_ids::bigint[]), 0) = 0
+        OR product_id = ANY(@product_ids::bigint[])
+    )
+ORDER BY sequence
+LIMIT @row_limit;
@@ -0,0 +1,31 @@
+-- name: CreateProductRelation :exec
+INSERT INTO
+    product_relation (
+        product_id,
+        related_product_id,
+        relation_type
+    )
+VALUES ($1, $2, $3) ON CONFLICT DO NOTHING;
+
+-- name: DeleteProductRelation :execrows
+DELETE FROM product_relation
+WHERE
+    product_id = $1
+    AND related_product_id = $2
+    AND relation_type = $3;
+
+-- name: GetProductRelations :many
+SELECT related_product_id, relation_type
+FROM product_relation
+WHERE product_id = $1
+ORDER BY relation_type, created_at;
+
+-- name: GetSimilarProductsByCategory :many
+SELECT *
+FROM product
+WHERE
+    category_id = @category_id
+    AND id <> @product_id
+    AND inventory > 0
+ORDER BY (coalesce(brand, '') = @brand::text) DESC, abs(price - @price)
+LIMIT @size;
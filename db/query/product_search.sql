@@ -0,0 +1,37 @@
+-- name: SearchProducts :many
+SELECT product.*
+FROM product
+    JOIN product_search ON product_search.product_id = product.id
+WHERE
+    (
+        product_search.document @@ websearch_to_tsquery('simple', fold_vietnamese(@query::text))
+        OR fold_vietnamese(@query::text) <% product_search.folded
+    )
+    AND (@category_id::bigint = 0 OR product.category_id = @category_id)
+    AND (@supplier_id::bigint = 0 OR product.supplier_id = @supplier_id)
+    AND (@brand::text = '' OR coalesce(product.brand, '') = @brand)
+    AND (@min_price::bigint = 0 OR product.price >= @min_price)
+    AND (@max_price::bigint = 0 OR product.price <= @max_price)
+ORDER BY
+    CASE WHEN @sort::text = 'time' THEN product.created_at END DESC,
+    CASE WHEN @sort::text = 'price_inc' THEN product.price END ASC,
+    CASE WHEN @sort::text = 'price_desc' THEN product.price END DESC,
+    ts_rank(product_search.document, websearch_to_tsquery('simple', fold_vietnamese(@query::text))) + word_similarity(fold_vietnamese(@query::text), product_search.folded) DESC,
+    product.id
+LIMIT @row_limit
+OFFSET @row_offset;
+
+-- name: CountSearchProducts :one
+SELECT count(*)
+FROM product
+    JOIN product_search ON product_search.product_id = product.id
+WHERE
+    (
+        product_search.document @@ websearch_to_tsquery('simple', fold_vietnamese(@query::text))
+        OR fold_vietnamese(@query::text) <% product_search.folded
+    )
+    AND (@category_id::bigint = 0 OR product.category_id = @category_id)
+    AND (@supplier_id::bigint = 0 OR product.supplier_id = @supplier_id)
+    AND (@brand::text = '' OR coalesce(product.brand, '') = @brand)
+    AND (@min_price::bigint = 0 OR product.price >= @min_price)
+    AND (@max_price::bigint = 0 OR product.price <= @max_price);
@@ -0,0 +1,36 @@
+-- name: UpsertProductTextVector :exec
+INSERT INTO
+    product_text_vector (product_id, terms)
+VALUES ($1, $2) ON CONFLICT (product_id) DO
+UPDATE
+SET
+    terms = EXCLUDED.terms,
+    updated_at = now();
+
+-- name: GetAllProductTextVector :many
+SELECT product_id, terms FROM product_text_vector;
+
+-- name: GetProductDocument :one
+SELECT
+    product.id,
+    product.name,
+    product.description,
+    product.brand,
+    category.name AS category_name
+FROM product
+    JOIN category ON category.id = product.category_id
+WHERE product.id = $1;
+
+-- name: GetProductDocumentsWithoutVector :many
+SELECT
+    product.id,
+    product.name,
+    product.description,
+    product.brand,
+    category.name AS category_name
+FROM product
+    JOIN category ON category.id = product.category_id
+WHERE product.id NOT IN (
+        SELECT product_id
+        FROM product_text_vector
+    );
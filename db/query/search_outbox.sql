@@ -0,0 +1,43 @@
+-- name: EnqueueSearchOutbox :exec
+INSERT INTO search_outbox (product_id, operation) VALUES ($1, $2);
+
+-- name: ClaimSearchOutbox :many
+UPDATE search_outbox
+SET next_attempt_at = @lease_until
+WHERE id IN (
+        SELECT id
+        FROM search_outbox
+        WHERE
+            status = 'pending'
+            AND next_attempt_at <= now()
+        ORDER BY id
+        LIMIT @row_limit
+        FOR UPDATE SKIP LOCKED
+    )
+RETURNING *;
+
+-- name: DeleteSearchOutbox :exec
+DELETE FROM search_outbox WHERE id = $1;
+
+-- name: RetrySearchOutbox :exec
+UPDATE search_outbox
+SET
+    status = @status,
+    attempts = attempts + 1,
+    last_error = @last_error,
+    next_attempt_at = @next_attempt_at
+WHERE id = @id;
+
+-- name: GetSearchOutboxLag :one
+SELECT
+    count(*) FILTER (WHERE status = 'pending') AS pending,
+    count(*) FILTER (WHERE status = 'dead') AS dead,
+    coalesce(
+        extract(
+            epoch
+            FROM
+                now() - min(created_at) FILTER (WHERE status = 'pending')
+        ),
+        0
+    )::float8 AS lag_seconds
+FROM search_outbox;
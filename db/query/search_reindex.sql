@@ -0,0 +1,26 @@
+-- name: CreateSearchReindex :one
+INSERT INTO search_reindex DEFAULT VALUES RETURNING *;
+
+-- name: GetSearchReindex :one
+SELECT * FROM search_reindex WHERE id = $1;
+
+-- name: UpdateSearchReindex :exec
+UPDATE search_reindex
+SET
+    last_product_id = $2,
+    indexed = $3,
+    status = $4,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: GetProductNamesAfter :many
+SELECT id, name FROM product WHERE id > $1 ORDER BY id LIMIT $2;
+
+-- name: CountProductsAfter :one
+SELECT count(*) FROM product WHERE id > $1;
+
+-- name: TryLockSearchReindex :one
+SELECT pg_try_advisory_lock(hashtext('search_reindex'), @id::integer)::bool AS locked;
+
+-- name: UnlockSearchReindex :exec
+SELECT pg_advisory_unlock(hashtext('search_reindex'), @id::integer);
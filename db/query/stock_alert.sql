@@ -0,0 +1,40 @@
+-- name: SetReorderThreshold :execrows
+UPDATE product
+SET reorder_threshold = $3
+WHERE id = $1 AND supplier_id = $2;
+
+-- name: GetProductThreshold :one
+SELECT supplier_id, inventory, reorder_threshold FROM product WHERE id = $1;
+
+-- name: CreateStockAlert :exec
+INSERT INTO
+    stock_alert (
+        product_id,
+        supplier_id,
+        alert_type,
+        inventory,
+        threshold
+    )
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetLowStockProducts :many
+SELECT *
+FROM product
+WHERE
+    supplier_id = $1
+    AND reorder_threshold > 0
+    AND inventory <= reorder_threshold
+ORDER BY inventory, id;
+
+-- name: GetStockAlertTxid :one
+SELECT txid FROM stock_alert WHERE id = $1 AND supplier_id = $2;
+
+-- name: GetStockAlertsAfter :many
+SELECT *
+FROM stock_alert
+WHERE
+    supplier_id = @supplier_id
+    AND (txid, id) > (@after_txid::bigint, @after_id::bigint)
+    AND txid < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+ORDER BY txid, id
+LIMIT @row_limit;
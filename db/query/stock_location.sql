@@ -0,0 +1,68 @@
+-- name: CreateStockLocation :one
+INSERT INTO
+    stock_location (supplier_id, name, priority)
+VALUES ($1, $2, $3) RETURNING *;
+
+-- name: GetStockLocationsBySupplier :many
+SELECT *
+FROM stock_location
+WHERE supplier_id = $1
+ORDER BY priority, id;
+
+-- name: GetStockLocation :one
+SELECT * FROM stock_location WHERE id = $1;
+
+-- name: GetProductStock :many
+SELECT
+    product_stock.location_id,
+    product_stock.quantity,
+    stock_location.name,
+    stock_location.priority
+FROM product_stock
+    JOIN stock_location ON stock_location.id = product_stock.location_id
+WHERE product_stock.product_id = $1
+ORDER BY stock_location.priority, product_stock.location_id;
+
+-- name: LockProductStock :many
+SELECT
+    product_stock.location_id,
+    product_stock.quantity,
+    stock_location.priority
+FROM product_stock
+    JOIN stock_location ON stock_location.id = product_stock.location_id
+WHERE product_stock.product_id = $1
+ORDER BY product_stock.location_id
+FOR UPDATE OF product_stock;
+
+-- name: UpsertProductStock :exec
+INSERT INTO
+    product_stock (
+        product_id,
+        location_id,
+        quantity
+    )
+VALUES ($1, $2, $3) ON CONFLICT (product_id, location_id) DO
+UPDATE
+SET
+    quantity = EXCLUDED.quantity;
+
+-- name: AddProductStock :execrows
+UPDATE product_stock
+SET
+    quantity = quantity + @delta::integer
+WHERE
+    product_id = @product_id
+    AND location_id = @location_id
+    AND quantity + @delta::integer >= 0;
+
+-- name: SyncProductInventory :one
+UPDATE product
+SET inventory = (
+        SELECT
+            coalesce(sum(quantity), 0)
+        FROM product_stock
+        WHERE
+            product_stock.product_id = product.id
+    ) + least(inventory, 0)
+WHERE product.id = $1
+RETURNING inventory;
@@ -0,0 +1,28 @@
+-- name: SetStockPolicy :execrows
+UPDATE product
+SET
+    stock_policy = $3,
+    backorder_limit = $4,
+    preorder_until = $5,
+    available_at = $6
+WHERE id = $1 AND supplier_id = $2;
+
+-- name: GetProductStockPolicy :one
+SELECT
+    inventory,
+    reserved,
+    stock_policy,
+    backorder_limit,
+    preorder_until
+FROM product
+WHERE id = $1;
+
+-- name: LockProductStockPolicy :one
+SELECT
+    inventory,
+    reserved,
+    stock_policy,
+    backorder_limit,
+    preorder_until
+FROM product
+WHERE id = $1 FOR UPDATE;
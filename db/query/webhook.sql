@@ -0,0 +1,107 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO
+    webhook_subscription (
+        supplier_id,
+        url,
+        event_types,
+        secret
+    )
+VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: GetWebhookSubscriptions :many
+SELECT *
+FROM webhook_subscription
+WHERE supplier_id = $1
+ORDER BY id;
+
+-- name: DeleteWebhookSubscription :execrows
+DELETE FROM webhook_subscription WHERE id = $1 AND supplier_id = $2;
+
+-- name: EnqueueWebhookDeliveries :exec
+INSERT INTO
+    webhook_delivery (
+        subscription_id,
+        event_type,
+        payload
+    )
+SELECT
+    id,
+    @event_type::text,
+    @payload::text
+FROM webhook_subscription
+WHERE
+    supplier_id = @supplier_id
+    AND @event_type::text = ANY(event_types);
+
+-- name: ClaimWebhookDeliveries :many
+WITH claimed AS (
+    UPDATE webhook_delivery
+    SET next_attempt_at = @lease_until
+    WHERE id IN (
+            SELECT id
+            FROM webhook_delivery
+            WHERE
+                status = 'pending'
+                AND next_attempt_at <= now()
+            ORDER BY id
+            LIMIT @row_limit
+            FOR UPDATE SKIP LOCKED
+        )
+    RETURNING *
+)
+SELECT
+    claimed.*,
+    webhook_subscription.url,
+    webhook_subscription.secret
+FROM claimed
+    JOIN webhook_subscription ON webhook_subscription.id = claimed.subscription_id
+ORDER BY claimed.id;
+
+-- name: MarkWebhookDelivered :exec
+UPDATE webhook_delivery
+SET
+    status = 'delivered',
+    attempts = attempts + 1,
+    response_status = $2,
+    last_error = '',
+    delivered_at = now()
+WHERE id = $1;
+
+-- name: RetryWebhookDelivery :exec
+UPDATE webhook_delivery
+SET
+    status = @status,
+    attempts = attempts + 1,
+    response_status = @response_status,
+    last_error = @last_error,
+    next_attempt_at = @next_attempt_at
+WHERE id = @id;
+
+-- name: GetDeadWebhookDeliveries :many
+SELECT webhook_delivery.*
+FROM webhook_delivery
+    JOIN webhook_subscription ON webhook_subscription.id = webhook_delivery.subscription_id
+WHERE
+    webhook_subscription.supplier_id = @supplier_id
+    AND (@subscription_id::bigint = 0 OR webhook_delivery.subscription_id = @subscription_id)
+    AND webhook_delivery.status = 'dead'
+ORDER BY webhook_delivery.id DESC
+LIMIT @row_limit
+OFFSET @row_offset;
+
+-- name: ReplayWebhookDeliveries :execrows
+UPDATE webhook_delivery
+SET
+    status = 'pending',
+    attempts = 0,
+    next_attempt_at = now()
+FROM webhook_subscription
+WHERE
+    webhook_subscription.id = webhook_delivery.subscription_id
+    AND webhook_subscription.supplier_id = @supplier_id
+    AND (@subscription_id::bigint = 0 OR webhook_delivery.subscription_id = @subscription_id)
+    AND (
+        coalesce(cardinality(@ids::bigint[]), 0) = 0
+        OR webhook_delivery.id = ANY(@ids::bigint[])
+    )
+    AND webhook_delivery.status = 'dead';
@@ -0,0 +1,84 @@
+package event
+
+import (
+	"context"
+	"sync"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+)
+
+// Bus hands events to subscribers of the same process, it is the publisher used
+// when no sink is configured and in tests
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives the events published after it was made. C is closed when the
+// subscription or the bus is closed, or when the subscriber fell behind by more than
+// its buffer, so a subscriber never misses events silently.
+type Subscription struct {
+	C <-chan *pb.ProductEvent
+
+	c   chan *pb.ProductEvent
+	bus *Bus
+}
+
+// NewBus creates a Bus without subscribers
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe starts receiving events, buffer events may wait for the subscriber
+func (bus *Bus) Subscribe(buffer int) *Subscription {
+	c := make(chan *pb.ProductEvent, buffer)
+	subscription := &Subscription{C: c, c: c, bus: bus}
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+// Close stops the subscription
+func (subscription *Subscription) Close() {
+	bus := subscription.bus
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.unsubscribe(subscription)
+}
+
+func (bus *Bus) unsubscribe(subscription *Subscription) {
+	if _, ok := bus.subscribers[subscription]; ok {
+		delete(bus.subscribers, subscription)
+		close(subscription.c)
+	}
+}
+
+// Publish never blocks, subscribers with a full buffer are dropped
+func (bus *Bus) Publish(_ context.Context, event *pb.ProductEvent) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	for subscription := range bus.subscribers {
+		select {
+		case subscription.c <- event:
+		default:
+			bus.unsubscribe(subscription)
+		}
+	}
+	return nil
+}
+
+// Close closes every subscription
+func (bus *Bus) Close() error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	for subscription := range bus.subscribers {
+		bus.unsubscribe(subscription)
+	}
+	return nil
+}
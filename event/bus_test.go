@@ -0,0 +1,127 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+)
+
+func productEvent(productID int64) *pb.ProductEvent {
+	return &pb.ProductEvent{Type: pb.ProductEventType_product_updated, ProductId: productID}
+}
+
+// received drains the events waiting in the subscription and tells whether it is closed
+func received(subscription *Subscription) ([]int64, bool) {
+	var ids []int64
+	for {
+		select {
+		case event, ok := <-subscription.C:
+			if !ok {
+				return ids, true
+			}
+			ids = append(ids, event.GetProductId())
+		default:
+			return ids, false
+		}
+	}
+}
+
+func TestBusFanOut(t *testing.T) {
+	tests := []struct {
+		name       string
+		buffers    []int
+		publish    []int64
+		closeFirst bool
+		want       [][]int64
+		wantClosed []bool
+	}{
+		{
+			name:       "every subscriber gets every event in order",
+			buffers:    []int{4, 4},
+			publish:    []int64{1, 2, 3},
+			want:       [][]int64{{1, 2, 3}, {1, 2, 3}},
+			wantClosed: []bool{false, false},
+		},
+		{
+			name:       "slow subscriber is dropped, others keep receiving",
+			buffers:    []int{1, 4},
+			publish:    []int64{1, 2, 3},
+			want:       [][]int64{{1}, {1, 2, 3}},
+			wantClosed: []bool{true, false},
+		},
+		{
+			name:       "closed subscription receives nothing",
+			buffers:    []int{4, 4},
+			publish:    []int64{1},
+			closeFirst: true,
+			want:       [][]int64{nil, {1}},
+			wantClosed: []bool{true, false},
+		},
+		{
+			name:       "nothing published",
+			buffers:    []int{4},
+			want:       [][]int64{nil},
+			wantClosed: []bool{false},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus := NewBus()
+			subscriptions := make([]*Subscription, 0, len(test.buffers))
+			for _, buffer := range test.buffers {
+				subscriptions = append(subscriptions, bus.Subscribe(buffer))
+			}
+			if test.closeFirst {
+				subscriptions[0].Close()
+			}
+			for _, id := range test.publish {
+				if err := bus.Publish(context.Background(), productEvent(id)); err != nil {
+					t.Fatalf("Publish() error = %v", err)
+				}
+			}
+
+			for i, subscription := range subscriptions {
+				ids, closed := received(subscription)
+				if !equalIDs(ids, test.want[i]) {
+					t.Errorf("subscriber %d received %v, want %v", i, ids, test.want[i])
+				}
+				if closed != test.wantClosed[i] {
+					t.Errorf("subscriber %d closed = %v, want %v", i, closed, test.wantClosed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBusClose(t *testing.T) {
+	bus := NewBus()
+	first := bus.Subscribe(1)
+	second := bus.Subscribe(1)
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	for i, subscription := range []*Subscription{first, second} {
+		if _, closed := received(subscription); !closed {
+			t.Errorf("subscriber %d is still open after the bus closed", i)
+		}
+	}
+
+	// closing again and publishing after close are harmless
+	first.Close()
+	if err := bus.Publish(context.Background(), productEvent(1)); err != nil {
+		t.Errorf("Publish() after close error = %v", err)
+	}
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
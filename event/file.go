@@ -0,0 +1,50 @@
+package event
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// FileSink appends every event to a file as a line of JSON
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+var jsonLine = protojson.MarshalOptions{UseProtoNames: true}
+
+// NewFileSink opens path for appending, creating it when missing
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Publish writes the event and syncs the file, so a published event survives a crash
+func (sink *FileSink) Publish(_ context.Context, event *pb.ProductEvent) error {
+	line, err := jsonLine.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if _, err := sink.file.Write(line); err != nil {
+		return err
+	}
+	return sink.file.Sync()
+}
+
+// Close closes the file
+func (sink *FileSink) Close() error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	return sink.file.Close()
+}
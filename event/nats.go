@@ -0,0 +1,164 @@
+package event
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultSubjectPrefix makes subjects like product.created
+const DefaultSubjectPrefix = "product"
+
+const natsTimeout = 5 * time.Second
+
+// NATS publishes protobuf encoded events to a NATS server with the plain text client
+// protocol, so anything answering INFO, PUB and PING the way NATS does can stand in for
+// the server. Every publish waits for the PONG of a following PING, which the server
+// sends only after it processed the PUB.
+type NATS struct {
+	addr          string
+	user          *url.Userinfo
+	subjectPrefix string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewNATS creates a publisher for the server at addr, it connects on the first publish
+// and again after a failed one
+func NewNATS(addr string, user *url.Userinfo, subjectPrefix string) *NATS {
+	return &NATS{
+		addr:          addr,
+		user:          user,
+		subjectPrefix: subjectPrefix,
+	}
+}
+
+type natsConnect struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+}
+
+func (nats *NATS) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: natsTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", nats.addr)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(natsTimeout))
+	reader := bufio.NewReader(conn)
+
+	info, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if !strings.HasPrefix(info, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("nats: unexpected greeting %q", strings.TrimSpace(info))
+	}
+
+	options := natsConnect{Name: "product-service"}
+	if nats.user != nil {
+		options.User = nats.user.Username()
+		options.Pass, _ = nats.user.Password()
+	}
+	connect, err := json.Marshal(options)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\n", connect); err != nil {
+		conn.Close()
+		return err
+	}
+
+	nats.conn = conn
+	nats.reader = reader
+	return nil
+}
+
+// Publish sends the event on <prefix>.<event name> and waits until the server has it
+func (nats *NATS) Publish(ctx context.Context, event *pb.ProductEvent) error {
+	data, err := proto.Marshal(event)
+	if err != nil {
+		return err
+	}
+	subject := nats.subjectPrefix + "." + Name(event.GetType())
+
+	nats.mu.Lock()
+	defer nats.mu.Unlock()
+
+	if nats.conn == nil {
+		if err := nats.connect(ctx); err != nil {
+			return err
+		}
+	}
+	if err := nats.publish(ctx, subject, data); err != nil {
+		nats.conn.Close()
+		nats.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (nats *NATS) publish(ctx context.Context, subject string, data []byte) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(natsTimeout)
+	}
+	nats.conn.SetDeadline(deadline)
+
+	message := make([]byte, 0, len(subject)+len(data)+32)
+	message = append(message, fmt.Sprintf("PUB %s %d\r\n", subject, len(data))...)
+	message = append(message, data...)
+	message = append(message, "\r\nPING\r\n"...)
+	if _, err := nats.conn.Write(message); err != nil {
+		return err
+	}
+
+	for {
+		line, err := nats.reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := nats.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New("nats: " + strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+		// +OK and INFO updates need no answer
+	}
+}
+
+// Close closes the connection
+func (nats *NATS) Close() error {
+	nats.mu.Lock()
+	defer nats.mu.Unlock()
+	if nats.conn == nil {
+		return nil
+	}
+	err := nats.conn.Close()
+	nats.conn = nil
+	return err
+}
@@ -0,0 +1,246 @@
+package event
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+type natsMessage struct {
+	subject string
+	event   *pb.ProductEvent
+}
+
+// fakeNATS answers the subset of the NATS client protocol the publisher uses
+type fakeNATS struct {
+	listener net.Listener
+	greeting string
+	// reply is written after the PING following a PUB, "PING" makes the server ping the
+	// client first and wait for its PONG
+	reply string
+	// dropAfter closes the first connection instead of replying to that many publishes,
+	// 0 never does
+	dropAfter int
+
+	mu          sync.Mutex
+	connections int
+	connects    []natsConnect
+	messages    []natsMessage
+	errors      []error
+}
+
+func newFakeNATS(t *testing.T, greeting, reply string, dropAfter int) *fakeNATS {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeNATS{listener: listener, greeting: greeting, reply: reply, dropAfter: dropAfter}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (server *fakeNATS) serve() {
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+		server.mu.Lock()
+		server.connections++
+		first := server.connections == 1
+		server.mu.Unlock()
+		go func() {
+			defer conn.Close()
+			if err := server.handle(conn, first); err != nil && err != io.EOF {
+				server.mu.Lock()
+				server.errors = append(server.errors, err)
+				server.mu.Unlock()
+			}
+		}()
+	}
+}
+
+func (server *fakeNATS) handle(conn net.Conn, first bool) error {
+	if _, err := io.WriteString(conn, server.greeting); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(server.greeting, "INFO ") {
+		return nil
+	}
+	reader := bufio.NewReader(conn)
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	var options natsConnect
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(line), "CONNECT ")), &options); err != nil {
+		return fmt.Errorf("bad CONNECT %q: %v", line, err)
+	}
+	server.mu.Lock()
+	server.connects = append(server.connects, options)
+	server.mu.Unlock()
+
+	for published := 1; ; published++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		var subject string
+		var size int
+		if _, err := fmt.Sscanf(line, "PUB %s %d\r\n", &subject, &size); err != nil {
+			return fmt.Errorf("bad PUB %q: %v", line, err)
+		}
+		payload := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			return err
+		}
+		event := &pb.ProductEvent{}
+		if err := proto.Unmarshal(payload[:size], event); err != nil {
+			return err
+		}
+		if ping, err := reader.ReadString('\n'); err != nil || ping != "PING\r\n" {
+			return fmt.Errorf("PUB not followed by PING: %q %v", ping, err)
+		}
+		server.mu.Lock()
+		server.messages = append(server.messages, natsMessage{subject: subject, event: event})
+		server.mu.Unlock()
+
+		if first && published == server.dropAfter {
+			return nil
+		}
+		if server.reply == "PING" {
+			if _, err := io.WriteString(conn, "PING\r\n"); err != nil {
+				return err
+			}
+			if pong, err := reader.ReadString('\n'); err != nil || pong != "PONG\r\n" {
+				return fmt.Errorf("server PING not answered: %q %v", pong, err)
+			}
+			if _, err := io.WriteString(conn, "PONG\r\n"); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := io.WriteString(conn, server.reply); err != nil {
+			return err
+		}
+	}
+}
+
+func (server *fakeNATS) check(t *testing.T) {
+	t.Helper()
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for _, err := range server.errors {
+		t.Errorf("fake server: %v", err)
+	}
+}
+
+func TestNATSPublish(t *testing.T) {
+	tests := []struct {
+		name     string
+		greeting string
+		reply    string
+		wantErr  string
+	}{
+		{name: "pong", greeting: "INFO {}\r\n", reply: "PONG\r\n"},
+		{name: "verbose ok before pong", greeting: "INFO {}\r\n", reply: "+OK\r\nPONG\r\n"},
+		{name: "info update before pong", greeting: "INFO {}\r\n", reply: "INFO {\"ldm\":false}\r\nPONG\r\n"},
+		{name: "server ping is answered", greeting: "INFO {}\r\n", reply: "PING"},
+		{name: "server error", greeting: "INFO {}\r\n", reply: "-ERR 'Permissions Violation'\r\n", wantErr: "nats: 'Permissions Violation'"},
+		{name: "not a nats server", greeting: "HTTP/1.1 400 Bad Request\r\n", wantErr: "unexpected greeting"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newFakeNATS(t, test.greeting, test.reply, 0)
+			nats := NewNATS(server.listener.Addr().String(), url.UserPassword("user", "secret"), "catalog")
+			defer nats.Close()
+
+			event := &pb.ProductEvent{Type: pb.ProductEventType_product_out_of_stock, ProductId: 42}
+			err := nats.Publish(context.Background(), event)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("Publish() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			server.check(t)
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			if len(server.connects) != 1 || server.connects[0].User != "user" || server.connects[0].Pass != "secret" {
+				t.Errorf("CONNECT = %+v, want user:secret", server.connects)
+			}
+			if len(server.messages) != 1 {
+				t.Fatalf("server received %d messages, want 1", len(server.messages))
+			}
+			message := server.messages[0]
+			if message.subject != "catalog.out_of_stock" {
+				t.Errorf("subject = %q, want catalog.out_of_stock", message.subject)
+			}
+			if !proto.Equal(message.event, event) {
+				t.Errorf("event = %v, want %v", message.event, event)
+			}
+		})
+	}
+}
+
+func TestNATSReconnect(t *testing.T) {
+	server := newFakeNATS(t, "INFO {}\r\n", "PONG\r\n", 2)
+	nats := NewNATS(server.listener.Addr().String(), nil, DefaultSubjectPrefix)
+	defer nats.Close()
+
+	wantErr := []bool{false, true, false, false}
+	for i, wantErr := range wantErr {
+		err := nats.Publish(context.Background(), productEvent(int64(i+1)))
+		if (err != nil) != wantErr {
+			t.Fatalf("publish %d error = %v, wantErr %v", i+1, err, wantErr)
+		}
+	}
+	server.check(t)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.connections != 2 {
+		t.Errorf("connections = %d, want 2", server.connections)
+	}
+	// the dropped publish reached the server but was not acknowledged, the outbox sends it again
+	var ids []int64
+	for _, message := range server.messages {
+		ids = append(ids, message.event.GetProductId())
+	}
+	if !equalIDs(ids, []int64{1, 2, 3, 4}) {
+		t.Errorf("server received %v, want [1 2 3 4]", ids)
+	}
+}
+
+func TestNATSUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	nats := NewNATS(addr, nil, DefaultSubjectPrefix)
+	if err := nats.Publish(context.Background(), productEvent(1)); err == nil {
+		t.Error("Publish() to a closed port succeeded")
+	}
+	if err := nats.Close(); err != nil {
+		t.Errorf("Close() without a connection error = %v", err)
+	}
+}
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
+	"time"
 
+	"github.com/e-commerce-microservices/product-service/event"
 	"github.com/e-commerce-microservices/product-service/pb"
 	"github.com/e-commerce-microservices/product-service/repository"
 	"github.com/e-commerce-microservices/product-service/service"
@@ -56,9 +60,6 @@ func jaegerTraceProvider() (*sdktrace.TracerProvider, error) {
 }
 
 func main() {
-	// create grpc server
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(otelgrpc.UnaryServerInterceptor()))
-
 	// init user db connection
 	pgDSN := fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
@@ -105,8 +106,66 @@ func main() {
 	}
 	searchClient := pb.NewSearchServiceClient(searchClientConn)
 
+	// dial auth client
+	authClientConn, err := grpc.Dial("auth-service:8080", grpc.WithInsecure())
+	if err != nil {
+		log.Fatal("can't dial auth service: ", err)
+	}
+	authClient := pb.NewAuthServiceClient(authClientConn)
+
 	// create product service
-	productService := service.NewProductService(imageClient, reviewClient, orderClient, searchClient, queries, productDB)
+	productService := service.NewProductService(imageClient, reviewClient, orderClient, searchClient, authClient, queries, productDB)
+	if strategy := os.Getenv("INVENTORY_ALLOCATION_STRATEGY"); strategy != "" {
+		if err := productService.SetAllocationStrategy(strategy); err != nil {
+			log.Fatal(err)
+		}
+	}
+	// the other services call the inventory methods with a token of SERVICE_TOKENS, name:token pairs
+	if err := productService.SetServiceTokens(os.Getenv("SERVICE_TOKENS")); err != nil {
+		log.Fatal(err)
+	}
+	if err := productService.LoadSimilarityIndex(context.Background()); err != nil {
+		log.Fatal("can't load similarity index: ", err)
+	}
+	if err := productService.LoadSuggestIndex(context.Background()); err != nil {
+		log.Fatal("can't load suggest index: ", err)
+	}
+	go productService.SweepExpiredReservations(context.Background(), time.Minute)
+	go productService.SweepFlashSales(context.Background(), time.Minute)
+
+	idempotencyRetention, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_RETENTION"))
+	if err != nil {
+		idempotencyRetention = service.DefaultIdempotencyRetention
+	}
+	go productService.SweepIdempotencyKeys(context.Background(), time.Hour, idempotencyRetention)
+	go productService.RelaySearchOutbox(context.Background(), 5*time.Second)
+
+	// product events go to PRODUCT_EVENT_SINK, a file:// or nats:// URL
+	if sink := os.Getenv("PRODUCT_EVENT_SINK"); sink != "" {
+		publisher, err := event.Open(sink)
+		if err != nil {
+			log.Fatal("can't open product event sink: ", err)
+		}
+		defer publisher.Close()
+		productService.SetEventPublisher(publisher)
+	}
+	go productService.PublishProductEvents(context.Background(), time.Second)
+	go productService.FollowProductChanges(context.Background(), 5*time.Minute)
+	go productService.DeliverWebhooks(context.Background(), 5*time.Second)
+
+	// expvar metrics are served on /debug/vars
+	metricsAddr := os.Getenv("METRICS_ADDR")
+	if metricsAddr == "" {
+		metricsAddr = ":9090"
+	}
+	go func() {
+		log.Println("metrics listener error: ", http.ListenAndServe(metricsAddr, nil))
+	}()
+	// create grpc server, calls are authorized with the caller's auth-service claims
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(otelgrpc.UnaryServerInterceptor(), productService.UnaryAuthInterceptor),
+		grpc.ChainStreamInterceptor(otelgrpc.StreamServerInterceptor(), productService.StreamAuthInterceptor),
+	)
 	// register product service
 	pb.RegisterProductServiceServer(grpcServer, productService)
 
@@ -39,6 +39,43 @@ type ProductServiceClient interface {
 	DescInventory(ctx context.Context, in *DescInventoryRequest, opts ...grpc.CallOption) (*DescInventoryResponse, error)
 	IncInventory(ctx context.Context, in *IncInventoryRequest, opts ...grpc.CallOption) (*IncInventoryResponse, error)
 	GetCategoryBySupplier(ctx context.Context, in *GetCategoryBySupplierRequest, opts ...grpc.CallOption) (*GetCategoryBySupplierResponse, error)
+	RecordCustomerActivity(ctx context.Context, in *RecordCustomerActivityRequest, opts ...grpc.CallOption) (*GeneralResponse, error)
+	AddProductRelation(ctx context.Context, in *ProductRelationRequest, opts ...grpc.CallOption) (*GeneralResponse, error)
+	RemoveProductRelation(ctx context.Context, in *ProductRelationRequest, opts ...grpc.CallOption) (*GeneralResponse, error)
+	GetRelatedProducts(ctx context.Context, in *GetRelatedProductsRequest, opts ...grpc.CallOption) (*GetRelatedProductsResponse, error)
+	GetSimilarProducts(ctx context.Context, in *GetSimilarProductsRequest, opts ...grpc.CallOption) (*GetListProductResponse, error)
+	ReserveInventory(ctx context.Context, in *ReserveInventoryRequest, opts ...grpc.CallOption) (*ReserveInventoryResponse, error)
+	CommitReservation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*GeneralResponse, error)
+	ReleaseReservation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*GeneralResponse, error)
+	BatchDescInventory(ctx context.Context, in *BatchDescInventoryRequest, opts ...grpc.CallOption) (*BatchDescInventoryResponse, error)
+	GetInventoryHistory(ctx context.Context, in *GetInventoryHistoryRequest, opts ...grpc.CallOption) (*GetInventoryHistoryResponse, error)
+	AdjustInventory(ctx context.Context, in *AdjustInventoryRequest, opts ...grpc.CallOption) (*GeneralResponse, error)
+	CheckInventoryConsistency(ctx context.Context, in *CheckInventoryConsistencyRequest, opts ...grpc.CallOption) (*CheckInventoryConsistencyResponse, error)
+	CreateStockLocation(ctx context.Context, in *CreateStockLocationRequest, opts ...grpc.CallOption) (*StockLocation, error)
+	GetStockLocations(ctx context.Context, in *GetStockLocationsRequest, opts ...grpc.CallOption) (*GetStockLocationsResponse, error)
+	SetLocationStock(ctx context.Context, in *SetLocationStockRequest, opts ...grpc.CallOption) (*GeneralResponse, error)
+	SetReorderThreshold(ctx context.Context, in *SetReorderThresholdRequest, opts ...grpc.CallOption) (*GeneralResponse, error)
+	ListLowStockProducts(ctx context.Context, in *ListLowStockProductsRequest, opts ...grpc.CallOption) (*GetListProductResponse, error)
+	WatchStockAlerts(ctx context.Context, in *WatchStockAlertsRequest, opts ...grpc.CallOption) (ProductService_WatchStockAlertsClient, error)
+	SetStockPolicy(ctx context.Context, in *SetStockPolicyRequest, opts ...grpc.CallOption) (*GeneralResponse, error)
+	GetInventoryAvailability(ctx context.Context, in *GetInventoryAvailabilityRequest, opts ...grpc.CallOption) (*GetInventoryAvailabilityResponse, error)
+	CreateFlashSale(ctx context.Context, in *CreateFlashSaleRequest, opts ...grpc.CallOption) (*FlashSale, error)
+	BuyFlashSale(ctx context.Context, in *BuyFlashSaleRequest, opts ...grpc.CallOption) (*BuyFlashSaleResponse, error)
+	ImportStockCount(ctx context.Context, in *ImportStockCountRequest, opts ...grpc.CallOption) (*ImportStockCountResponse, error)
+	TryDeductInventory(ctx context.Context, in *TryDeductInventoryRequest, opts ...grpc.CallOption) (*DeductionResponse, error)
+	ConfirmDeduction(ctx context.Context, in *DeductionRequest, opts ...grpc.CallOption) (*DeductionResponse, error)
+	CancelDeduction(ctx context.Context, in *DeductionRequest, opts ...grpc.CallOption) (*DeductionResponse, error)
+	ReindexSearch(ctx context.Context, in *ReindexSearchRequest, opts ...grpc.CallOption) (ProductService_ReindexSearchClient, error)
+	SearchProducts(ctx context.Context, in *SearchProductsRequest, opts ...grpc.CallOption) (*SearchProductsResponse, error)
+	SuggestProducts(ctx context.Context, in *SuggestProductsRequest, opts ...grpc.CallOption) (*SuggestProductsResponse, error)
+	SearchAndHydrate(ctx context.Context, in *SearchAndHydrateRequest, opts ...grpc.CallOption) (*SearchProductsResponse, error)
+	WatchProducts(ctx context.Context, in *WatchProductsRequest, opts ...grpc.CallOption) (ProductService_WatchProductsClient, error)
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*GeneralResponse, error)
+	ListDeadWebhookDeliveries(ctx context.Context, in *ListDeadWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListDeadWebhookDeliveriesResponse, error)
+	ReplayWebhookDeliveries(ctx context.Context, in *ReplayWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ReplayWebhookDeliveriesResponse, error)
+	ApproveProduct(ctx context.Context, in *ApproveProductRequest, opts ...grpc.CallOption) (*GeneralResponse, error)
 }
 
 type productServiceClient struct {
@@ -193,6 +230,408 @@ func (c *productServiceClient) GetCategoryBySupplier(ctx context.Context, in *Ge
 	return out, nil
 }
 
+func (c *productServiceClient) RecordCustomerActivity(ctx context.Context, in *RecordCustomerActivityRequest, opts ...grpc.CallOption) (*GeneralResponse, error) {
+	out := new(GeneralResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/RecordCustomerActivity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) AddProductRelation(ctx context.Context, in *ProductRelationRequest, opts ...grpc.CallOption) (*GeneralResponse, error) {
+	out := new(GeneralResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/AddProductRelation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) RemoveProductRelation(ctx context.Context, in *ProductRelationRequest, opts ...grpc.CallOption) (*GeneralResponse, error) {
+	out := new(GeneralResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/RemoveProductRelation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetRelatedProducts(ctx context.Context, in *GetRelatedProductsRequest, opts ...grpc.CallOption) (*GetRelatedProductsResponse, error) {
+	out := new(GetRelatedProductsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/GetRelatedProducts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetSimilarProducts(ctx context.Context, in *GetSimilarProductsRequest, opts ...grpc.CallOption) (*GetListProductResponse, error) {
+	out := new(GetListProductResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/GetSimilarProducts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ReserveInventory(ctx context.Context, in *ReserveInventoryRequest, opts ...grpc.CallOption) (*ReserveInventoryResponse, error) {
+	out := new(ReserveInventoryResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/ReserveInventory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) CommitReservation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*GeneralResponse, error) {
+	out := new(GeneralResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/CommitReservation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ReleaseReservation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*GeneralResponse, error) {
+	out := new(GeneralResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/ReleaseReservation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) BatchDescInventory(ctx context.Context, in *BatchDescInventoryRequest, opts ...grpc.CallOption) (*BatchDescInventoryResponse, error) {
+	out := new(BatchDescInventoryResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/BatchDescInventory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetInventoryHistory(ctx context.Context, in *GetInventoryHistoryRequest, opts ...grpc.CallOption) (*GetInventoryHistoryResponse, error) {
+	out := new(GetInventoryHistoryResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/GetInventoryHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) AdjustInventory(ctx context.Context, in *AdjustInventoryRequest, opts ...grpc.CallOption) (*GeneralResponse, error) {
+	out := new(GeneralResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/AdjustInventory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) CheckInventoryConsistency(ctx context.Context, in *CheckInventoryConsistencyRequest, opts ...grpc.CallOption) (*CheckInventoryConsistencyResponse, error) {
+	out := new(CheckInventoryConsistencyResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/CheckInventoryConsistency", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) CreateStockLocation(ctx context.Context, in *CreateStockLocationRequest, opts ...grpc.CallOption) (*StockLocation, error) {
+	out := new(StockLocation)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/CreateStockLocation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetStockLocations(ctx context.Context, in *GetStockLocationsRequest, opts ...grpc.CallOption) (*GetStockLocationsResponse, error) {
+	out := new(GetStockLocationsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/GetStockLocations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) SetLocationStock(ctx context.Context, in *SetLocationStockRequest, opts ...grpc.CallOption) (*GeneralResponse, error) {
+	out := new(GeneralResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/SetLocationStock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) SetReorderThreshold(ctx context.Context, in *SetReorderThresholdRequest, opts ...grpc.CallOption) (*GeneralResponse, error) {
+	out := new(GeneralResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/SetReorderThreshold", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ListLowStockProducts(ctx context.Context, in *ListLowStockProductsRequest, opts ...grpc.CallOption) (*GetListProductResponse, error) {
+	out := new(GetListProductResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/ListLowStockProducts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) WatchStockAlerts(ctx context.Context, in *WatchStockAlertsRequest, opts ...grpc.CallOption) (ProductService_WatchStockAlertsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[0], "/ecommerce.ProductService/WatchStockAlerts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &productServiceWatchStockAlertsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ProductService_WatchStockAlertsClient interface {
+	Recv() (*StockAlert, error)
+	grpc.ClientStream
+}
+
+type productServiceWatchStockAlertsClient struct {
+	grpc.ClientStream
+}
+
+func (x *productServiceWatchStockAlertsClient) Recv() (*StockAlert, error) {
+	m := new(StockAlert)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *productServiceClient) SetStockPolicy(ctx context.Context, in *SetStockPolicyRequest, opts ...grpc.CallOption) (*GeneralResponse, error) {
+	out := new(GeneralResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/SetStockPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) GetInventoryAvailability(ctx context.Context, in *GetInventoryAvailabilityRequest, opts ...grpc.CallOption) (*GetInventoryAvailabilityResponse, error) {
+	out := new(GetInventoryAvailabilityResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/GetInventoryAvailability", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) CreateFlashSale(ctx context.Context, in *CreateFlashSaleRequest, opts ...grpc.CallOption) (*FlashSale, error) {
+	out := new(FlashSale)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/CreateFlashSale", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) BuyFlashSale(ctx context.Context, in *BuyFlashSaleRequest, opts ...grpc.CallOption) (*BuyFlashSaleResponse, error) {
+	out := new(BuyFlashSaleResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/BuyFlashSale", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ImportStockCount(ctx context.Context, in *ImportStockCountRequest, opts ...grpc.CallOption) (*ImportStockCountResponse, error) {
+	out := new(ImportStockCountResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/ImportStockCount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) TryDeductInventory(ctx context.Context, in *TryDeductInventoryRequest, opts ...grpc.CallOption) (*DeductionResponse, error) {
+	out := new(DeductionResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/TryDeductInventory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ConfirmDeduction(ctx context.Context, in *DeductionRequest, opts ...grpc.CallOption) (*DeductionResponse, error) {
+	out := new(DeductionResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/ConfirmDeduction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) CancelDeduction(ctx context.Context, in *DeductionRequest, opts ...grpc.CallOption) (*DeductionResponse, error) {
+	out := new(DeductionResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/CancelDeduction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ReindexSearch(ctx context.Context, in *ReindexSearchRequest, opts ...grpc.CallOption) (ProductService_ReindexSearchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[1], "/ecommerce.ProductService/ReindexSearch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &productServiceReindexSearchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ProductService_ReindexSearchClient interface {
+	Recv() (*ReindexProgress, error)
+	grpc.ClientStream
+}
+
+type productServiceReindexSearchClient struct {
+	grpc.ClientStream
+}
+
+func (x *productServiceReindexSearchClient) Recv() (*ReindexProgress, error) {
+	m := new(ReindexProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *productServiceClient) SearchProducts(ctx context.Context, in *SearchProductsRequest, opts ...grpc.CallOption) (*SearchProductsResponse, error) {
+	out := new(SearchProductsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/SearchProducts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) SuggestProducts(ctx context.Context, in *SuggestProductsRequest, opts ...grpc.CallOption) (*SuggestProductsResponse, error) {
+	out := new(SuggestProductsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/SuggestProducts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) SearchAndHydrate(ctx context.Context, in *SearchAndHydrateRequest, opts ...grpc.CallOption) (*SearchProductsResponse, error) {
+	out := new(SearchProductsResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/SearchAndHydrate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) WatchProducts(ctx context.Context, in *WatchProductsRequest, opts ...grpc.CallOption) (ProductService_WatchProductsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ProductService_ServiceDesc.Streams[2], "/ecommerce.ProductService/WatchProducts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &productServiceWatchProductsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ProductService_WatchProductsClient interface {
+	Recv() (*ProductEvent, error)
+	grpc.ClientStream
+}
+
+type productServiceWatchProductsClient struct {
+	grpc.ClientStream
+}
+
+func (x *productServiceWatchProductsClient) Recv() (*ProductEvent, error) {
+	m := new(ProductEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *productServiceClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/CreateWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/ListWebhooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*GeneralResponse, error) {
+	out := new(GeneralResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/DeleteWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ListDeadWebhookDeliveries(ctx context.Context, in *ListDeadWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListDeadWebhookDeliveriesResponse, error) {
+	out := new(ListDeadWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/ListDeadWebhookDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ReplayWebhookDeliveries(ctx context.Context, in *ReplayWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ReplayWebhookDeliveriesResponse, error) {
+	out := new(ReplayWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/ReplayWebhookDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ApproveProduct(ctx context.Context, in *ApproveProductRequest, opts ...grpc.CallOption) (*GeneralResponse, error) {
+	out := new(GeneralResponse)
+	err := c.cc.Invoke(ctx, "/ecommerce.ProductService/ApproveProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProductServiceServer is the server API for ProductService service.
 // All implementations must embed UnimplementedProductServiceServer
 // for forward compatibility
@@ -213,358 +652,1181 @@ type ProductServiceServer interface {
 	DescInventory(context.Context, *DescInventoryRequest) (*DescInventoryResponse, error)
 	IncInventory(context.Context, *IncInventoryRequest) (*IncInventoryResponse, error)
 	GetCategoryBySupplier(context.Context, *GetCategoryBySupplierRequest) (*GetCategoryBySupplierResponse, error)
+	RecordCustomerActivity(context.Context, *RecordCustomerActivityRequest) (*GeneralResponse, error)
+	AddProductRelation(context.Context, *ProductRelationRequest) (*GeneralResponse, error)
+	RemoveProductRelation(context.Context, *ProductRelationRequest) (*GeneralResponse, error)
+	GetRelatedProducts(context.Context, *GetRelatedProductsRequest) (*GetRelatedProductsResponse, error)
+	GetSimilarProducts(context.Context, *GetSimilarProductsRequest) (*GetListProductResponse, error)
+	ReserveInventory(context.Context, *ReserveInventoryRequest) (*ReserveInventoryResponse, error)
+	CommitReservation(context.Context, *ReservationRequest) (*GeneralResponse, error)
+	ReleaseReservation(context.Context, *ReservationRequest) (*GeneralResponse, error)
+	BatchDescInventory(context.Context, *BatchDescInventoryRequest) (*BatchDescInventoryResponse, error)
+	GetInventoryHistory(context.Context, *GetInventoryHistoryRequest) (*GetInventoryHistoryResponse, error)
+	AdjustInventory(context.Context, *AdjustInventoryRequest) (*GeneralResponse, error)
+	CheckInventoryConsistency(context.Context, *CheckInventoryConsistencyRequest) (*CheckInventoryConsistencyResponse, error)
+	CreateStockLocation(context.Context, *CreateStockLocationRequest) (*StockLocation, error)
+	GetStockLocations(context.Context, *GetStockLocationsRequest) (*GetStockLocationsResponse, error)
+	SetLocationStock(context.Context, *SetLocationStockRequest) (*GeneralResponse, error)
+	SetReorderThreshold(context.Context, *SetReorderThresholdRequest) (*GeneralResponse, error)
+	ListLowStockProducts(context.Context, *ListLowStockProductsRequest) (*GetListProductResponse, error)
+	WatchStockAlerts(*WatchStockAlertsRequest, ProductService_WatchStockAlertsServer) error
+	SetStockPolicy(context.Context, *SetStockPolicyRequest) (*GeneralResponse, error)
+	GetInventoryAvailability(context.Context, *GetInventoryAvailabilityRequest) (*GetInventoryAvailabilityResponse, error)
+	CreateFlashSale(context.Context, *CreateFlashSaleRequest) (*FlashSale, error)
+	BuyFlashSale(context.Context, *BuyFlashSaleRequest) (*BuyFlashSaleResponse, error)
+	ImportStockCount(context.Context, *ImportStockCountRequest) (*ImportStockCountResponse, error)
+	TryDeductInventory(context.Context, *TryDeductInventoryRequest) (*DeductionResponse, error)
+	ConfirmDeduction(context.Context, *DeductionRequest) (*DeductionResponse, error)
+	CancelDeduction(context.Context, *DeductionRequest) (*DeductionResponse, error)
+	ReindexSearch(*ReindexSearchRequest, ProductService_ReindexSearchServer) error
+	SearchProducts(context.Context, *SearchProductsRequest) (*SearchProductsResponse, error)
+	SuggestProducts(context.Context, *SuggestProductsRequest) (*SuggestProductsResponse, error)
+	SearchAndHydrate(context.Context, *SearchAndHydrateRequest) (*SearchProductsResponse, error)
+	WatchProducts(*WatchProductsRequest, ProductService_WatchProductsServer) error
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*Webhook, error)
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*GeneralResponse, error)
+	ListDeadWebhookDeliveries(context.Context, *ListDeadWebhookDeliveriesRequest) (*ListDeadWebhookDeliveriesResponse, error)
+	ReplayWebhookDeliveries(context.Context, *ReplayWebhookDeliveriesRequest) (*ReplayWebhookDeliveriesResponse, error)
+	ApproveProduct(context.Context, *ApproveProductRequest) (*GeneralResponse, error)
+	mustEmbedUnimplementedProductServiceServer()
+}
+
+// UnimplementedProductServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedProductServiceServer struct {
+}
+
+func (UnimplementedProductServiceServer) Ping(context.Context, *empty.Empty) (*Pong, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (UnimplementedProductServiceServer) CreateProduct(context.Context, *CreateProductRequest) (*CreateProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProduct not implemented")
+}
+func (UnimplementedProductServiceServer) GetProduct(context.Context, *GetProductRequest) (*Product, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProduct not implemented")
+}
+func (UnimplementedProductServiceServer) GetListProduct(context.Context, *GetListProductRequest) (*GetListProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetListProduct not implemented")
+}
+func (UnimplementedProductServiceServer) GetListProductByIDs(context.Context, *GetListProductByIDsRequest) (*GetListProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetListProductByIDs not implemented")
+}
+func (UnimplementedProductServiceServer) GetRecomendProduct(context.Context, *GetRecommendProductRequest) (*GetListProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecomendProduct not implemented")
+}
+func (UnimplementedProductServiceServer) DeleteProduct(context.Context, *DeleteProductRequest) (*DeleteProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProduct not implemented")
+}
+func (UnimplementedProductServiceServer) DeleteProductByAdmin(context.Context, *DeleteProductByAdminRequest) (*DeleteProductByAdminResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProductByAdmin not implemented")
+}
+func (UnimplementedProductServiceServer) GetProductBySupplier(context.Context, *GetProductBySupplierRequest) (*GetListProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductBySupplier not implemented")
+}
+func (UnimplementedProductServiceServer) UpdateProduct(context.Context, *UpdateProductRequest) (*GeneralResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProduct not implemented")
+}
+func (UnimplementedProductServiceServer) CreateCategory(context.Context, *CreateCategoryRequest) (*GeneralResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCategory not implemented")
+}
+func (UnimplementedProductServiceServer) GetListCategory(context.Context, *empty.Empty) (*GetListCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetListCategory not implemented")
+}
+func (UnimplementedProductServiceServer) GetListProductInventory(context.Context, *GetInventoryRequest) (*GetInventoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetListProductInventory not implemented")
+}
+func (UnimplementedProductServiceServer) DescInventory(context.Context, *DescInventoryRequest) (*DescInventoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescInventory not implemented")
+}
+func (UnimplementedProductServiceServer) IncInventory(context.Context, *IncInventoryRequest) (*IncInventoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IncInventory not implemented")
+}
+func (UnimplementedProductServiceServer) GetCategoryBySupplier(context.Context, *GetCategoryBySupplierRequest) (*GetCategoryBySupplierResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCategoryBySupplier not implemented")
+}
+func (UnimplementedProductServiceServer) RecordCustomerActivity(context.Context, *RecordCustomerActivityRequest) (*GeneralResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordCustomerActivity not implemented")
+}
+func (UnimplementedProductServiceServer) AddProductRelation(context.Context, *ProductRelationRequest) (*GeneralResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddProductRelation not implemented")
+}
+func (UnimplementedProductServiceServer) RemoveProductRelation(context.Context, *ProductRelationRequest) (*GeneralResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveProductRelation not implemented")
+}
+func (UnimplementedProductServiceServer) GetRelatedProducts(context.Context, *GetRelatedProductsRequest) (*GetRelatedProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRelatedProducts not implemented")
+}
+func (UnimplementedProductServiceServer) GetSimilarProducts(context.Context, *GetSimilarProductsRequest) (*GetListProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSimilarProducts not implemented")
+}
+func (UnimplementedProductServiceServer) ReserveInventory(context.Context, *ReserveInventoryRequest) (*ReserveInventoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveInventory not implemented")
+}
+func (UnimplementedProductServiceServer) CommitReservation(context.Context, *ReservationRequest) (*GeneralResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitReservation not implemented")
+}
+func (UnimplementedProductServiceServer) ReleaseReservation(context.Context, *ReservationRequest) (*GeneralResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseReservation not implemented")
+}
+func (UnimplementedProductServiceServer) BatchDescInventory(context.Context, *BatchDescInventoryRequest) (*BatchDescInventoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDescInventory not implemented")
+}
+func (UnimplementedProductServiceServer) GetInventoryHistory(context.Context, *GetInventoryHistoryRequest) (*GetInventoryHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInventoryHistory not implemented")
+}
+func (UnimplementedProductServiceServer) AdjustInventory(context.Context, *AdjustInventoryRequest) (*GeneralResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdjustInventory not implemented")
+}
+func (UnimplementedProductServiceServer) CheckInventoryConsistency(context.Context, *CheckInventoryConsistencyRequest) (*CheckInventoryConsistencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckInventoryConsistency not implemented")
+}
+func (UnimplementedProductServiceServer) CreateStockLocation(context.Context, *CreateStockLocationRequest) (*StockLocation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateStockLocation not implemented")
+}
+func (UnimplementedProductServiceServer) GetStockLocations(context.Context, *GetStockLocationsRequest) (*GetStockLocationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStockLocations not implemented")
+}
+func (UnimplementedProductServiceServer) SetLocationStock(context.Context, *SetLocationStockRequest) (*GeneralResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLocationStock not implemented")
+}
+func (UnimplementedProductServiceServer) SetReorderThreshold(context.Context, *SetReorderThresholdRequest) (*GeneralResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetReorderThreshold not implemented")
+}
+func (UnimplementedProductServiceServer) ListLowStockProducts(context.Context, *ListLowStockProductsRequest) (*GetListProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLowStockProducts not implemented")
+}
+func (UnimplementedProductServiceServer) WatchStockAlerts(*WatchStockAlertsRequest, ProductService_WatchStockAlertsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStockAlerts not implemented")
+}
+func (UnimplementedProductServiceServer) SetStockPolicy(context.Context, *SetStockPolicyRequest) (*GeneralResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetStockPolicy not implemented")
+}
+func (UnimplementedProductServiceServer) GetInventoryAvailability(context.Context, *GetInventoryAvailabilityRequest) (*GetInventoryAvailabilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInventoryAvailability not implemented")
+}
+func (UnimplementedProductServiceServer) CreateFlashSale(context.Context, *CreateFlashSaleRequest) (*FlashSale, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFlashSale not implemented")
+}
+func (UnimplementedProductServiceServer) BuyFlashSale(context.Context, *BuyFlashSaleRequest) (*BuyFlashSaleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BuyFlashSale not implemented")
+}
+func (UnimplementedProductServiceServer) ImportStockCount(context.Context, *ImportStockCountRequest) (*ImportStockCountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportStockCount not implemented")
+}
+func (UnimplementedProductServiceServer) TryDeductInventory(context.Context, *TryDeductInventoryRequest) (*DeductionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TryDeductInventory not implemented")
+}
+func (UnimplementedProductServiceServer) ConfirmDeduction(context.Context, *DeductionRequest) (*DeductionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmDeduction not implemented")
+}
+func (UnimplementedProductServiceServer) CancelDeduction(context.Context, *DeductionRequest) (*DeductionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelDeduction not implemented")
+}
+func (UnimplementedProductServiceServer) ReindexSearch(*ReindexSearchRequest, ProductService_ReindexSearchServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReindexSearch not implemented")
+}
+func (UnimplementedProductServiceServer) SearchProducts(context.Context, *SearchProductsRequest) (*SearchProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchProducts not implemented")
+}
+func (UnimplementedProductServiceServer) SuggestProducts(context.Context, *SuggestProductsRequest) (*SuggestProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestProducts not implemented")
+}
+func (UnimplementedProductServiceServer) SearchAndHydrate(context.Context, *SearchAndHydrateRequest) (*SearchProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchAndHydrate not implemented")
+}
+func (UnimplementedProductServiceServer) WatchProducts(*WatchProductsRequest, ProductService_WatchProductsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchProducts not implemented")
+}
+func (UnimplementedProductServiceServer) CreateWebhook(context.Context, *CreateWebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedProductServiceServer) ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedProductServiceServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*GeneralResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedProductServiceServer) ListDeadWebhookDeliveries(context.Context, *ListDeadWebhookDeliveriesRequest) (*ListDeadWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadWebhookDeliveries not implemented")
+}
+func (UnimplementedProductServiceServer) ReplayWebhookDeliveries(context.Context, *ReplayWebhookDeliveriesRequest) (*ReplayWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayWebhookDeliveries not implemented")
+}
+func (UnimplementedProductServiceServer) ApproveProduct(context.Context, *ApproveProductRequest) (*GeneralResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveProduct not implemented")
+}
+func (UnimplementedProductServiceServer) mustEmbedUnimplementedProductServiceServer() {}
+
+// UnsafeProductServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProductServiceServer will
+// result in compilation errors.
+type UnsafeProductServiceServer interface {
 	mustEmbedUnimplementedProductServiceServer()
 }
 
-// UnimplementedProductServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedProductServiceServer struct {
+func RegisterProductServiceServer(s grpc.ServiceRegistrar, srv ProductServiceServer) {
+	s.RegisterService(&ProductService_ServiceDesc, srv)
+}
+
+func _ProductService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/Ping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).Ping(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_CreateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CreateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/CreateProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CreateProduct(ctx, req.(*CreateProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/GetProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetProduct(ctx, req.(*GetProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetListProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetListProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetListProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/GetListProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetListProduct(ctx, req.(*GetListProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetListProductByIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetListProductByIDsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetListProductByIDs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/GetListProductByIDs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetListProductByIDs(ctx, req.(*GetListProductByIDsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetRecomendProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecommendProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetRecomendProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/GetRecomendProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetRecomendProduct(ctx, req.(*GetRecommendProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_DeleteProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).DeleteProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/DeleteProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).DeleteProduct(ctx, req.(*DeleteProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_DeleteProductByAdmin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProductByAdminRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).DeleteProductByAdmin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/DeleteProductByAdmin",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).DeleteProductByAdmin(ctx, req.(*DeleteProductByAdminRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetProductBySupplier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductBySupplierRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetProductBySupplier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/GetProductBySupplier",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetProductBySupplier(ctx, req.(*GetProductBySupplierRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_UpdateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).UpdateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/UpdateProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).UpdateProduct(ctx, req.(*UpdateProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_CreateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CreateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/CreateCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CreateCategory(ctx, req.(*CreateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetListCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetListCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/GetListCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetListCategory(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetListProductInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetListProductInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/GetListProductInventory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetListProductInventory(ctx, req.(*GetInventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (UnimplementedProductServiceServer) Ping(context.Context, *empty.Empty) (*Pong, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+func _ProductService_DescInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescInventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).DescInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/DescInventory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).DescInventory(ctx, req.(*DescInventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) CreateProduct(context.Context, *CreateProductRequest) (*CreateProductResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateProduct not implemented")
+
+func _ProductService_IncInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncInventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).IncInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/IncInventory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).IncInventory(ctx, req.(*IncInventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) GetProduct(context.Context, *GetProductRequest) (*Product, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetProduct not implemented")
+
+func _ProductService_GetCategoryBySupplier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCategoryBySupplierRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetCategoryBySupplier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/GetCategoryBySupplier",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetCategoryBySupplier(ctx, req.(*GetCategoryBySupplierRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) GetListProduct(context.Context, *GetListProductRequest) (*GetListProductResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetListProduct not implemented")
+
+func _ProductService_RecordCustomerActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordCustomerActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).RecordCustomerActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/RecordCustomerActivity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).RecordCustomerActivity(ctx, req.(*RecordCustomerActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) GetListProductByIDs(context.Context, *GetListProductByIDsRequest) (*GetListProductResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetListProductByIDs not implemented")
+
+func _ProductService_AddProductRelation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductRelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).AddProductRelation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/AddProductRelation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).AddProductRelation(ctx, req.(*ProductRelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) GetRecomendProduct(context.Context, *GetRecommendProductRequest) (*GetListProductResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetRecomendProduct not implemented")
+
+func _ProductService_RemoveProductRelation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductRelationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).RemoveProductRelation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/RemoveProductRelation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).RemoveProductRelation(ctx, req.(*ProductRelationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) DeleteProduct(context.Context, *DeleteProductRequest) (*DeleteProductResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteProduct not implemented")
+
+func _ProductService_GetRelatedProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRelatedProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetRelatedProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/GetRelatedProducts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetRelatedProducts(ctx, req.(*GetRelatedProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) DeleteProductByAdmin(context.Context, *DeleteProductByAdminRequest) (*DeleteProductByAdminResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteProductByAdmin not implemented")
+
+func _ProductService_GetSimilarProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSimilarProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetSimilarProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/GetSimilarProducts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetSimilarProducts(ctx, req.(*GetSimilarProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) GetProductBySupplier(context.Context, *GetProductBySupplierRequest) (*GetListProductResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetProductBySupplier not implemented")
+
+func _ProductService_ReserveInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveInventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ReserveInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/ReserveInventory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ReserveInventory(ctx, req.(*ReserveInventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) UpdateProduct(context.Context, *UpdateProductRequest) (*GeneralResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateProduct not implemented")
+
+func _ProductService_CommitReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CommitReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/CommitReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CommitReservation(ctx, req.(*ReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ReleaseReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ReleaseReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/ReleaseReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ReleaseReservation(ctx, req.(*ReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_BatchDescInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDescInventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).BatchDescInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/BatchDescInventory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).BatchDescInventory(ctx, req.(*BatchDescInventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetInventoryHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInventoryHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetInventoryHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/GetInventoryHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetInventoryHistory(ctx, req.(*GetInventoryHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_AdjustInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdjustInventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).AdjustInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/AdjustInventory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).AdjustInventory(ctx, req.(*AdjustInventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_CheckInventoryConsistency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckInventoryConsistencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CheckInventoryConsistency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/CheckInventoryConsistency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CheckInventoryConsistency(ctx, req.(*CheckInventoryConsistencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_CreateStockLocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateStockLocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CreateStockLocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/CreateStockLocation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CreateStockLocation(ctx, req.(*CreateStockLocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_GetStockLocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStockLocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).GetStockLocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/GetStockLocations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).GetStockLocations(ctx, req.(*GetStockLocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) CreateCategory(context.Context, *CreateCategoryRequest) (*GeneralResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateCategory not implemented")
+
+func _ProductService_SetLocationStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLocationStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).SetLocationStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/SetLocationStock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).SetLocationStock(ctx, req.(*SetLocationStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) GetListCategory(context.Context, *empty.Empty) (*GetListCategoryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetListCategory not implemented")
+
+func _ProductService_SetReorderThreshold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetReorderThresholdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).SetReorderThreshold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/SetReorderThreshold",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).SetReorderThreshold(ctx, req.(*SetReorderThresholdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) GetListProductInventory(context.Context, *GetInventoryRequest) (*GetInventoryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetListProductInventory not implemented")
+
+func _ProductService_ListLowStockProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLowStockProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ListLowStockProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/ListLowStockProducts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ListLowStockProducts(ctx, req.(*ListLowStockProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedProductServiceServer) DescInventory(context.Context, *DescInventoryRequest) (*DescInventoryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DescInventory not implemented")
+
+func _ProductService_WatchStockAlerts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchStockAlertsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).WatchStockAlerts(m, &productServiceWatchStockAlertsServer{stream})
 }
-func (UnimplementedProductServiceServer) IncInventory(context.Context, *IncInventoryRequest) (*IncInventoryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method IncInventory not implemented")
+
+type ProductService_WatchStockAlertsServer interface {
+	Send(*StockAlert) error
+	grpc.ServerStream
 }
-func (UnimplementedProductServiceServer) GetCategoryBySupplier(context.Context, *GetCategoryBySupplierRequest) (*GetCategoryBySupplierResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetCategoryBySupplier not implemented")
+
+type productServiceWatchStockAlertsServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedProductServiceServer) mustEmbedUnimplementedProductServiceServer() {}
 
-// UnsafeProductServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to ProductServiceServer will
-// result in compilation errors.
-type UnsafeProductServiceServer interface {
-	mustEmbedUnimplementedProductServiceServer()
+func (x *productServiceWatchStockAlertsServer) Send(m *StockAlert) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func RegisterProductServiceServer(s grpc.ServiceRegistrar, srv ProductServiceServer) {
-	s.RegisterService(&ProductService_ServiceDesc, srv)
+func _ProductService_SetStockPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStockPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).SetStockPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.ProductService/SetStockPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).SetStockPolicy(ctx, req.(*SetStockPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+func _ProductService_GetInventoryAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInventoryAvailabilityRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).Ping(ctx, in)
+		return srv.(ProductServiceServer).GetInventoryAvailability(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/Ping",
+		FullMethod: "/ecommerce.ProductService/GetInventoryAvailability",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).Ping(ctx, req.(*empty.Empty))
+		return srv.(ProductServiceServer).GetInventoryAvailability(ctx, req.(*GetInventoryAvailabilityRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_CreateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateProductRequest)
+func _ProductService_CreateFlashSale_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFlashSaleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).CreateProduct(ctx, in)
+		return srv.(ProductServiceServer).CreateFlashSale(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/CreateProduct",
+		FullMethod: "/ecommerce.ProductService/CreateFlashSale",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).CreateProduct(ctx, req.(*CreateProductRequest))
+		return srv.(ProductServiceServer).CreateFlashSale(ctx, req.(*CreateFlashSaleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetProductRequest)
+func _ProductService_BuyFlashSale_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuyFlashSaleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetProduct(ctx, in)
+		return srv.(ProductServiceServer).BuyFlashSale(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/GetProduct",
+		FullMethod: "/ecommerce.ProductService/BuyFlashSale",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetProduct(ctx, req.(*GetProductRequest))
+		return srv.(ProductServiceServer).BuyFlashSale(ctx, req.(*BuyFlashSaleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetListProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetListProductRequest)
+func _ProductService_ImportStockCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportStockCountRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetListProduct(ctx, in)
+		return srv.(ProductServiceServer).ImportStockCount(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/GetListProduct",
+		FullMethod: "/ecommerce.ProductService/ImportStockCount",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetListProduct(ctx, req.(*GetListProductRequest))
+		return srv.(ProductServiceServer).ImportStockCount(ctx, req.(*ImportStockCountRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetListProductByIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetListProductByIDsRequest)
+func _ProductService_TryDeductInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TryDeductInventoryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetListProductByIDs(ctx, in)
+		return srv.(ProductServiceServer).TryDeductInventory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/GetListProductByIDs",
+		FullMethod: "/ecommerce.ProductService/TryDeductInventory",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetListProductByIDs(ctx, req.(*GetListProductByIDsRequest))
+		return srv.(ProductServiceServer).TryDeductInventory(ctx, req.(*TryDeductInventoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetRecomendProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetRecommendProductRequest)
+func _ProductService_ConfirmDeduction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeductionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetRecomendProduct(ctx, in)
+		return srv.(ProductServiceServer).ConfirmDeduction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/GetRecomendProduct",
+		FullMethod: "/ecommerce.ProductService/ConfirmDeduction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetRecomendProduct(ctx, req.(*GetRecommendProductRequest))
+		return srv.(ProductServiceServer).ConfirmDeduction(ctx, req.(*DeductionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_DeleteProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteProductRequest)
+func _ProductService_CancelDeduction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeductionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).DeleteProduct(ctx, in)
+		return srv.(ProductServiceServer).CancelDeduction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/DeleteProduct",
+		FullMethod: "/ecommerce.ProductService/CancelDeduction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).DeleteProduct(ctx, req.(*DeleteProductRequest))
+		return srv.(ProductServiceServer).CancelDeduction(ctx, req.(*DeductionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_DeleteProductByAdmin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteProductByAdminRequest)
+func _ProductService_ReindexSearch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReindexSearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).ReindexSearch(m, &productServiceReindexSearchServer{stream})
+}
+
+type ProductService_ReindexSearchServer interface {
+	Send(*ReindexProgress) error
+	grpc.ServerStream
+}
+
+type productServiceReindexSearchServer struct {
+	grpc.ServerStream
+}
+
+func (x *productServiceReindexSearchServer) Send(m *ReindexProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ProductService_SearchProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchProductsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).DeleteProductByAdmin(ctx, in)
+		return srv.(ProductServiceServer).SearchProducts(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/DeleteProductByAdmin",
+		FullMethod: "/ecommerce.ProductService/SearchProducts",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).DeleteProductByAdmin(ctx, req.(*DeleteProductByAdminRequest))
+		return srv.(ProductServiceServer).SearchProducts(ctx, req.(*SearchProductsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetProductBySupplier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetProductBySupplierRequest)
+func _ProductService_SuggestProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestProductsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetProductBySupplier(ctx, in)
+		return srv.(ProductServiceServer).SuggestProducts(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/GetProductBySupplier",
+		FullMethod: "/ecommerce.ProductService/SuggestProducts",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetProductBySupplier(ctx, req.(*GetProductBySupplierRequest))
+		return srv.(ProductServiceServer).SuggestProducts(ctx, req.(*SuggestProductsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_UpdateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateProductRequest)
+func _ProductService_SearchAndHydrate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchAndHydrateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).UpdateProduct(ctx, in)
+		return srv.(ProductServiceServer).SearchAndHydrate(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/UpdateProduct",
+		FullMethod: "/ecommerce.ProductService/SearchAndHydrate",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).UpdateProduct(ctx, req.(*UpdateProductRequest))
+		return srv.(ProductServiceServer).SearchAndHydrate(ctx, req.(*SearchAndHydrateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_CreateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateCategoryRequest)
+func _ProductService_WatchProducts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchProductsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductServiceServer).WatchProducts(m, &productServiceWatchProductsServer{stream})
+}
+
+type ProductService_WatchProductsServer interface {
+	Send(*ProductEvent) error
+	grpc.ServerStream
+}
+
+type productServiceWatchProductsServer struct {
+	grpc.ServerStream
+}
+
+func (x *productServiceWatchProductsServer) Send(m *ProductEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ProductService_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).CreateCategory(ctx, in)
+		return srv.(ProductServiceServer).CreateWebhook(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/CreateCategory",
+		FullMethod: "/ecommerce.ProductService/CreateWebhook",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).CreateCategory(ctx, req.(*CreateCategoryRequest))
+		return srv.(ProductServiceServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetListCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+func _ProductService_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetListCategory(ctx, in)
+		return srv.(ProductServiceServer).ListWebhooks(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/GetListCategory",
+		FullMethod: "/ecommerce.ProductService/ListWebhooks",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetListCategory(ctx, req.(*empty.Empty))
+		return srv.(ProductServiceServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetListProductInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetInventoryRequest)
+func _ProductService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetListProductInventory(ctx, in)
+		return srv.(ProductServiceServer).DeleteWebhook(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/GetListProductInventory",
+		FullMethod: "/ecommerce.ProductService/DeleteWebhook",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetListProductInventory(ctx, req.(*GetInventoryRequest))
+		return srv.(ProductServiceServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_DescInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DescInventoryRequest)
+func _ProductService_ListDeadWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadWebhookDeliveriesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).DescInventory(ctx, in)
+		return srv.(ProductServiceServer).ListDeadWebhookDeliveries(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/DescInventory",
+		FullMethod: "/ecommerce.ProductService/ListDeadWebhookDeliveries",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).DescInventory(ctx, req.(*DescInventoryRequest))
+		return srv.(ProductServiceServer).ListDeadWebhookDeliveries(ctx, req.(*ListDeadWebhookDeliveriesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_IncInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(IncInventoryRequest)
+func _ProductService_ReplayWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayWebhookDeliveriesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).IncInventory(ctx, in)
+		return srv.(ProductServiceServer).ReplayWebhookDeliveries(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/IncInventory",
+		FullMethod: "/ecommerce.ProductService/ReplayWebhookDeliveries",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).IncInventory(ctx, req.(*IncInventoryRequest))
+		return srv.(ProductServiceServer).ReplayWebhookDeliveries(ctx, req.(*ReplayWebhookDeliveriesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProductService_GetCategoryBySupplier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetCategoryBySupplierRequest)
+func _ProductService_ApproveProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveProductRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProductServiceServer).GetCategoryBySupplier(ctx, in)
+		return srv.(ProductServiceServer).ApproveProduct(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/ecommerce.ProductService/GetCategoryBySupplier",
+		FullMethod: "/ecommerce.ProductService/ApproveProduct",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProductServiceServer).GetCategoryBySupplier(ctx, req.(*GetCategoryBySupplierRequest))
+		return srv.(ProductServiceServer).ApproveProduct(ctx, req.(*ApproveProductRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -640,7 +1902,159 @@ var ProductService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCategoryBySupplier",
 			Handler:    _ProductService_GetCategoryBySupplier_Handler,
 		},
+		{
+			MethodName: "RecordCustomerActivity",
+			Handler:    _ProductService_RecordCustomerActivity_Handler,
+		},
+		{
+			MethodName: "AddProductRelation",
+			Handler:    _ProductService_AddProductRelation_Handler,
+		},
+		{
+			MethodName: "RemoveProductRelation",
+			Handler:    _ProductService_RemoveProductRelation_Handler,
+		},
+		{
+			MethodName: "GetRelatedProducts",
+			Handler:    _ProductService_GetRelatedProducts_Handler,
+		},
+		{
+			MethodName: "GetSimilarProducts",
+			Handler:    _ProductService_GetSimilarProducts_Handler,
+		},
+		{
+			MethodName: "ReserveInventory",
+			Handler:    _ProductService_ReserveInventory_Handler,
+		},
+		{
+			MethodName: "CommitReservation",
+			Handler:    _ProductService_CommitReservation_Handler,
+		},
+		{
+			MethodName: "ReleaseReservation",
+			Handler:    _ProductService_ReleaseReservation_Handler,
+		},
+		{
+			MethodName: "BatchDescInventory",
+			Handler:    _ProductService_BatchDescInventory_Handler,
+		},
+		{
+			MethodName: "GetInventoryHistory",
+			Handler:    _ProductService_GetInventoryHistory_Handler,
+		},
+		{
+			MethodName: "AdjustInventory",
+			Handler:    _ProductService_AdjustInventory_Handler,
+		},
+		{
+			MethodName: "CheckInventoryConsistency",
+			Handler:    _ProductService_CheckInventoryConsistency_Handler,
+		},
+		{
+			MethodName: "CreateStockLocation",
+			Handler:    _ProductService_CreateStockLocation_Handler,
+		},
+		{
+			MethodName: "GetStockLocations",
+			Handler:    _ProductService_GetStockLocations_Handler,
+		},
+		{
+			MethodName: "SetLocationStock",
+			Handler:    _ProductService_SetLocationStock_Handler,
+		},
+		{
+			MethodName: "SetReorderThreshold",
+			Handler:    _ProductService_SetReorderThreshold_Handler,
+		},
+		{
+			MethodName: "ListLowStockProducts",
+			Handler:    _ProductService_ListLowStockProducts_Handler,
+		},
+		{
+			MethodName: "SetStockPolicy",
+			Handler:    _ProductService_SetStockPolicy_Handler,
+		},
+		{
+			MethodName: "GetInventoryAvailability",
+			Handler:    _ProductService_GetInventoryAvailability_Handler,
+		},
+		{
+			MethodName: "CreateFlashSale",
+			Handler:    _ProductService_CreateFlashSale_Handler,
+		},
+		{
+			MethodName: "BuyFlashSale",
+			Handler:    _ProductService_BuyFlashSale_Handler,
+		},
+		{
+			MethodName: "ImportStockCount",
+			Handler:    _ProductService_ImportStockCount_Handler,
+		},
+		{
+			MethodName: "TryDeductInventory",
+			Handler:    _ProductService_TryDeductInventory_Handler,
+		},
+		{
+			MethodName: "ConfirmDeduction",
+			Handler:    _ProductService_ConfirmDeduction_Handler,
+		},
+		{
+			MethodName: "CancelDeduction",
+			Handler:    _ProductService_CancelDeduction_Handler,
+		},
+		{
+			MethodName: "SearchProducts",
+			Handler:    _ProductService_SearchProducts_Handler,
+		},
+		{
+			MethodName: "SuggestProducts",
+			Handler:    _ProductService_SuggestProducts_Handler,
+		},
+		{
+			MethodName: "SearchAndHydrate",
+			Handler:    _ProductService_SearchAndHydrate_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _ProductService_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _ProductService_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _ProductService_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListDeadWebhookDeliveries",
+			Handler:    _ProductService_ListDeadWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "ReplayWebhookDeliveries",
+			Handler:    _ProductService_ReplayWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "ApproveProduct",
+			Handler:    _ProductService_ApproveProduct_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchStockAlerts",
+			Handler:       _ProductService_WatchStockAlerts_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReindexSearch",
+			Handler:       _ProductService_ReindexSearch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchProducts",
+			Handler:       _ProductService_WatchProducts_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "product_service.proto",
 }
@@ -26,6 +26,7 @@ type SearchServiceClient interface {
 	Ping(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*Pong, error)
 	SearchProduct(ctx context.Context, in *SearchProductRequest, opts ...grpc.CallOption) (*SearchProductResponse, error)
 	AddProduct(ctx context.Context, in *AddProductRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	RemoveProduct(ctx context.Context, in *RemoveProductRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 }
 
 type searchServiceClient struct {
@@ -63,6 +64,15 @@ func (c *searchServiceClient) AddProduct(ctx context.Context, in *AddProductRequ
 	return out, nil
 }
 
+func (c *searchServiceClient) RemoveProduct(ctx context.Context, in *RemoveProductRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/ecommerce.SearchService/RemoveProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SearchServiceServer is the server API for SearchService service.
 // All implementations must embed UnimplementedSearchServiceServer
 // for forward compatibility
@@ -70,6 +80,7 @@ type SearchServiceServer interface {
 	Ping(context.Context, *empty.Empty) (*Pong, error)
 	SearchProduct(context.Context, *SearchProductRequest) (*SearchProductResponse, error)
 	AddProduct(context.Context, *AddProductRequest) (*empty.Empty, error)
+	RemoveProduct(context.Context, *RemoveProductRequest) (*empty.Empty, error)
 	mustEmbedUnimplementedSearchServiceServer()
 }
 
@@ -86,6 +97,9 @@ func (UnimplementedSearchServiceServer) SearchProduct(context.Context, *SearchPr
 func (UnimplementedSearchServiceServer) AddProduct(context.Context, *AddProductRequest) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddProduct not implemented")
 }
+func (UnimplementedSearchServiceServer) RemoveProduct(context.Context, *RemoveProductRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveProduct not implemented")
+}
 func (UnimplementedSearchServiceServer) mustEmbedUnimplementedSearchServiceServer() {}
 
 // UnsafeSearchServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -153,6 +167,24 @@ func _SearchService_AddProduct_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SearchService_RemoveProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SearchServiceServer).RemoveProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ecommerce.SearchService/RemoveProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SearchServiceServer).RemoveProduct(ctx, req.(*RemoveProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SearchService_ServiceDesc is the grpc.ServiceDesc for SearchService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -172,6 +204,10 @@ var SearchService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddProduct",
 			Handler:    _SearchService_AddProduct_Handler,
 		},
+		{
+			MethodName: "RemoveProduct",
+			Handler:    _SearchService_RemoveProduct_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "search_service.proto",
@@ -0,0 +1,197 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: customer_activity.sql
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+const getCustomerProfile = `-- name: GetCustomerProfile :many
+SELECT
+    customer_activity.product_id,
+    customer_activity.activity_type,
+    customer_activity.hit,
+    product.category_id,
+    product.brand,
+    product.price
+FROM customer_activity
+    JOIN product ON product.id = customer_activity.product_id
+WHERE customer_activity.customer_id = $1
+`
+
+type GetCustomerProfileRow struct {
+	ProductID    int64
+	ActivityType string
+	Hit          int32
+	CategoryID   int64
+	Brand        sql.NullString
+	Price        int64
+}
+
+func (q *Queries) GetCustomerProfile(ctx context.Context, customerID int64) ([]GetCustomerProfileRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCustomerProfile, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCustomerProfileRow
+	for rows.Next() {
+		var i GetCustomerProfileRow
+		if err := rows.Scan(
+			&i.ProductID,
+			&i.ActivityType,
+			&i.Hit,
+			&i.CategoryID,
+			&i.Brand,
+			&i.Price,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProductPopularity = `-- name: GetProductPopularity :many
+SELECT
+    product_id,
+    coalesce(sum(hit) FILTER (WHERE activity_type = 'viewed'), 0)::bigint AS viewed,
+    coalesce(sum(hit) FILTER (WHERE activity_type = 'purchased'), 0)::bigint AS purchased
+FROM customer_activity
+GROUP BY product_id
+`
+
+type GetProductPopularityRow struct {
+	ProductID int64
+	Viewed    int64
+	Purchased int64
+}
+
+func (q *Queries) GetProductPopularity(ctx context.Context) ([]GetProductPopularityRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProductPopularity)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProductPopularityRow
+	for rows.Next() {
+		var i GetProductPopularityRow
+		if err := rows.Scan(&i.ProductID, &i.Viewed, &i.Purchased); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecommendCandidates = `-- name: GetRecommendCandidates :many
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved
+FROM product
+WHERE
+    inventory > 0
+    AND id NOT IN (
+        SELECT product_id
+        FROM customer_activity
+        WHERE
+            customer_activity.customer_id = $1
+            AND activity_type = 'purchased'
+    )
+ORDER BY (
+        category_id = ANY($2 :: bigint [])
+    ) DESC,
+    created_at DESC
+LIMIT $3
+`
+
+type GetRecommendCandidatesParams struct {
+	CustomerID  int64
+	CategoryIds []int64
+	PoolSize    int32
+}
+
+func (q *Queries) GetRecommendCandidates(ctx context.Context, arg GetRecommendCandidatesParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, getRecommendCandidates, arg.CustomerID, pq.Array(arg.CategoryIds), arg.PoolSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.Thumbnail,
+			&i.Inventory,
+			&i.SupplierID,
+			&i.CategoryID,
+			&i.CreatedAt,
+			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertCustomerActivity = `-- name: UpsertCustomerActivity :exec
+INSERT INTO
+    customer_activity (
+        customer_id,
+        product_id,
+        activity_type
+    )
+VALUES ($1, $2, $3) ON CONFLICT (
+        customer_id,
+        product_id,
+        activity_type
+    ) DO
+UPDATE
+SET
+    hit = customer_activity.hit + 1,
+    updated_at = now()
+`
+
+type UpsertCustomerActivityParams struct {
+	CustomerID   int64
+	ProductID    int64
+	ActivityType string
+}
+
+func (q *Queries) UpsertCustomerActivity(ctx context.Context, arg UpsertCustomerActivityParams) error {
+	_, err := q.db.ExecContext(ctx, upsertCustomerActivity, arg.CustomerID, arg.ProductID, arg.ActivityType)
+	return err
+}
@@ -0,0 +1,350 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: flash_sale.sql
+
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+const addFlashSalePurchase = `-- name: AddFlashSalePurchase :execrows
+INSERT INTO
+    flash_sale_purchase (
+        flash_sale_id,
+        customer_id,
+        quantity
+    )
+VALUES ($1, $2, $3) ON CONFLICT (flash_sale_id, customer_id) DO
+UPDATE
+SET
+    quantity = flash_sale_purchase.quantity + EXCLUDED.quantity
+WHERE
+    flash_sale_purchase.quantity + EXCLUDED.quantity <= $4::integer
+`
+
+type AddFlashSalePurchaseParams struct {
+	FlashSaleID      int64
+	CustomerID       int64
+	Quantity         int32
+	PerCustomerLimit int32
+}
+
+func (q *Queries) AddFlashSalePurchase(ctx context.Context, arg AddFlashSalePurchaseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, addFlashSalePurchase,
+		arg.FlashSaleID,
+		arg.CustomerID,
+		arg.Quantity,
+		arg.PerCustomerLimit,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countOverlappingFlashSales = `-- name: CountOverlappingFlashSales :one
+SELECT count(*)
+FROM flash_sale
+WHERE
+    product_id = $1
+    AND status = 'active'
+    AND starts_at < $2
+    AND ends_at > $3
+`
+
+type CountOverlappingFlashSalesParams struct {
+	ProductID int64
+	EndsAt    time.Time
+	StartsAt  time.Time
+}
+
+func (q *Queries) CountOverlappingFlashSales(ctx context.Context, arg CountOverlappingFlashSalesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOverlappingFlashSales, arg.ProductID, arg.EndsAt, arg.StartsAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createFlashSale = `-- name: CreateFlashSale :one
+INSERT INTO
+    flash_sale (
+        product_id,
+        supplier_id,
+        sale_price,
+        starts_at,
+        ends_at,
+        allocated,
+        remaining,
+        per_customer_limit,
+        location_id
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $6, $7, $8) RETURNING id, product_id, supplier_id, sale_price, starts_at, ends_at, allocated, remaining, per_customer_limit, location_id, status, created_at, held
+`
+
+type CreateFlashSaleParams struct {
+	ProductID        int64
+	SupplierID       int64
+	SalePrice        int64
+	StartsAt         time.Time
+	EndsAt           time.Time
+	Allocated        int32
+	PerCustomerLimit int32
+	LocationID       int64
+}
+
+func (q *Queries) CreateFlashSale(ctx context.Context, arg CreateFlashSaleParams) (FlashSale, error) {
+	row := q.db.QueryRowContext(ctx, createFlashSale,
+		arg.ProductID,
+		arg.SupplierID,
+		arg.SalePrice,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.Allocated,
+		arg.PerCustomerLimit,
+		arg.LocationID,
+	)
+	var i FlashSale
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.SupplierID,
+		&i.SalePrice,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Allocated,
+		&i.Remaining,
+		&i.PerCustomerLimit,
+		&i.LocationID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.Held,
+	)
+	return i, err
+}
+
+const descFlashSale = `-- name: DescFlashSale :one
+UPDATE flash_sale
+SET
+    remaining = remaining - $1::integer
+WHERE
+    id = $2
+    AND status = 'active'
+    AND held
+    AND remaining >= $1::integer
+    AND starts_at <= now()
+    AND ends_at > now()
+RETURNING remaining
+`
+
+type DescFlashSaleParams struct {
+	Count int32
+	ID    int64
+}
+
+func (q *Queries) DescFlashSale(ctx context.Context, arg DescFlashSaleParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, descFlashSale, arg.Count, arg.ID)
+	var remaining int32
+	err := row.Scan(&remaining)
+	return remaining, err
+}
+
+const endFlashSale = `-- name: EndFlashSale :one
+UPDATE flash_sale
+SET status = 'ended'
+WHERE
+    id = $1
+    AND status = 'active'
+    AND ends_at <= now()
+RETURNING id, product_id, supplier_id, sale_price, starts_at, ends_at, allocated, remaining, per_customer_limit, location_id, status, created_at, held
+`
+
+func (q *Queries) EndFlashSale(ctx context.Context, id int64) (FlashSale, error) {
+	row := q.db.QueryRowContext(ctx, endFlashSale, id)
+	var i FlashSale
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.SupplierID,
+		&i.SalePrice,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Allocated,
+		&i.Remaining,
+		&i.PerCustomerLimit,
+		&i.LocationID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.Held,
+	)
+	return i, err
+}
+
+const getCurrentFlashSale = `-- name: GetCurrentFlashSale :one
+SELECT id, product_id, supplier_id, sale_price, starts_at, ends_at, allocated, remaining, per_customer_limit, location_id, status, created_at, held
+FROM flash_sale
+WHERE
+    product_id = $1
+    AND status = 'active'
+    AND remaining > 0
+    AND starts_at <= now()
+    AND ends_at > now()
+ORDER BY starts_at
+LIMIT 1
+`
+
+func (q *Queries) GetCurrentFlashSale(ctx context.Context, productID int64) (FlashSale, error) {
+	row := q.db.QueryRowContext(ctx, getCurrentFlashSale, productID)
+	var i FlashSale
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.SupplierID,
+		&i.SalePrice,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Allocated,
+		&i.Remaining,
+		&i.PerCustomerLimit,
+		&i.LocationID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.Held,
+	)
+	return i, err
+}
+
+const getEndedFlashSales = `-- name: GetEndedFlashSales :many
+SELECT id
+FROM flash_sale
+WHERE
+    status = 'active'
+    AND ends_at <= now()
+ORDER BY id
+LIMIT $1
+`
+
+func (q *Queries) GetEndedFlashSales(ctx context.Context, limit int32) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getEndedFlashSales, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFlashSale = `-- name: GetFlashSale :one
+SELECT id, product_id, supplier_id, sale_price, starts_at, ends_at, allocated, remaining, per_customer_limit, location_id, status, created_at, held FROM flash_sale WHERE id = $1
+`
+
+func (q *Queries) GetFlashSale(ctx context.Context, id int64) (FlashSale, error) {
+	row := q.db.QueryRowContext(ctx, getFlashSale, id)
+	var i FlashSale
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.SupplierID,
+		&i.SalePrice,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Allocated,
+		&i.Remaining,
+		&i.PerCustomerLimit,
+		&i.LocationID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.Held,
+	)
+	return i, err
+}
+
+const getStartingFlashSales = `-- name: GetStartingFlashSales :many
+SELECT id
+FROM flash_sale
+WHERE
+    status = 'active'
+    AND NOT held
+    AND starts_at <= now()
+    AND ends_at > now()
+ORDER BY id
+LIMIT $1
+`
+
+func (q *Queries) GetStartingFlashSales(ctx context.Context, limit int32) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getStartingFlashSales, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const startFlashSale = `-- name: StartFlashSale :one
+UPDATE flash_sale
+SET
+    held = true,
+    remaining = $1::integer
+WHERE
+    id = $2
+    AND status = 'active'
+    AND NOT held
+    AND starts_at <= now()
+    AND ends_at > now()
+RETURNING id, product_id, supplier_id, sale_price, starts_at, ends_at, allocated, remaining, per_customer_limit, location_id, status, created_at, held
+`
+
+type StartFlashSaleParams struct {
+	Remaining int32
+	ID        int64
+}
+
+func (q *Queries) StartFlashSale(ctx context.Context, arg StartFlashSaleParams) (FlashSale, error) {
+	row := q.db.QueryRowContext(ctx, startFlashSale, arg.Remaining, arg.ID)
+	var i FlashSale
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.SupplierID,
+		&i.SalePrice,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Allocated,
+		&i.Remaining,
+		&i.PerCustomerLimit,
+		&i.LocationID,
+		&i.Status,
+		&i.CreatedAt,
+		&i.Held,
+	)
+	return i, err
+}
@@ -0,0 +1,80 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: inventory_idempotency.sql
+
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+const claimIdempotencyKey = `-- name: ClaimIdempotencyKey :execrows
+INSERT INTO
+    inventory_idempotency ("key", "method", "request_hash")
+VALUES ($1, $2, $3) ON CONFLICT DO NOTHING
+`
+
+type ClaimIdempotencyKeyParams struct {
+	Key         string
+	Method      string
+	RequestHash []byte
+}
+
+func (q *Queries) ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimIdempotencyKey, arg.Key, arg.Method, arg.RequestHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteIdempotencyKeysBefore = `-- name: DeleteIdempotencyKeysBefore :execrows
+DELETE FROM inventory_idempotency WHERE created_at < $1
+`
+
+func (q *Queries) DeleteIdempotencyKeysBefore(ctx context.Context, createdAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteIdempotencyKeysBefore, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT key, method, response, error_status, created_at, request_hash FROM inventory_idempotency WHERE "key" = $1
+`
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, key string) (InventoryIdempotency, error) {
+	row := q.db.QueryRowContext(ctx, getIdempotencyKey, key)
+	var i InventoryIdempotency
+	err := row.Scan(
+		&i.Key,
+		&i.Method,
+		&i.Response,
+		&i.ErrorStatus,
+		&i.CreatedAt,
+		&i.RequestHash,
+	)
+	return i, err
+}
+
+const saveIdempotencyResult = `-- name: SaveIdempotencyResult :exec
+UPDATE inventory_idempotency
+SET
+    response = $2,
+    error_status = $3
+WHERE "key" = $1
+`
+
+type SaveIdempotencyResultParams struct {
+	Key         string
+	Response    []byte
+	ErrorStatus []byte
+}
+
+func (q *Queries) SaveIdempotencyResult(ctx context.Context, arg SaveIdempotencyResultParams) error {
+	_, err := q.db.ExecContext(ctx, saveIdempotencyResult, arg.Key, arg.Response, arg.ErrorStatus)
+	return err
+}
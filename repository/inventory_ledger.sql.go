@@ -0,0 +1,195 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: inventory_ledger.sql
+
+package repository
+
+import (
+	"context"
+)
+
+const adjustInventory = `-- name: AdjustInventory :one
+UPDATE product
+SET
+    inventory = inventory + $1::integer
+WHERE
+    id = $2
+    AND inventory + $1::integer >= 0
+RETURNING inventory
+`
+
+type AdjustInventoryParams struct {
+	Delta int32
+	ID    int64
+}
+
+func (q *Queries) AdjustInventory(ctx context.Context, arg AdjustInventoryParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, adjustInventory, arg.Delta, arg.ID)
+	var inventory int32
+	err := row.Scan(&inventory)
+	return inventory, err
+}
+
+const getInventoryHistory = `-- name: GetInventoryHistory :many
+SELECT id, product_id, delta, balance, reason, actor, reference, created_at, note
+FROM inventory_ledger
+WHERE product_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type GetInventoryHistoryParams struct {
+	ProductID int64
+	Limit     int32
+	Offset    int32
+}
+
+func (q *Queries) GetInventoryHistory(ctx context.Context, arg GetInventoryHistoryParams) ([]InventoryLedger, error) {
+	rows, err := q.db.QueryContext(ctx, getInventoryHistory, arg.ProductID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InventoryLedger
+	for rows.Next() {
+		var i InventoryLedger
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Delta,
+			&i.Balance,
+			&i.Reason,
+			&i.Actor,
+			&i.Reference,
+			&i.CreatedAt,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInventoryLedger = `-- name: GetInventoryLedger :many
+SELECT id, product_id, delta, balance, reason, actor, reference, created_at, note FROM inventory_ledger WHERE product_id = $1 ORDER BY id
+`
+
+func (q *Queries) GetInventoryLedger(ctx context.Context, productID int64) ([]InventoryLedger, error) {
+	rows, err := q.db.QueryContext(ctx, getInventoryLedger, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InventoryLedger
+	for rows.Next() {
+		var i InventoryLedger
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Delta,
+			&i.Balance,
+			&i.Reason,
+			&i.Actor,
+			&i.Reference,
+			&i.CreatedAt,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInventoryMismatches = `-- name: GetInventoryMismatches :many
+SELECT
+    product.id,
+    product.inventory,
+    coalesce(sum(inventory_ledger.delta), 0)::integer AS ledger_balance
+FROM product
+    LEFT JOIN inventory_ledger ON inventory_ledger.product_id = product.id
+GROUP BY product.id
+HAVING
+    product.inventory <> coalesce(sum(inventory_ledger.delta), 0)
+`
+
+type GetInventoryMismatchesRow struct {
+	ID            int64
+	Inventory     int32
+	LedgerBalance int32
+}
+
+func (q *Queries) GetInventoryMismatches(ctx context.Context) ([]GetInventoryMismatchesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getInventoryMismatches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetInventoryMismatchesRow
+	for rows.Next() {
+		var i GetInventoryMismatchesRow
+		if err := rows.Scan(&i.ID, &i.Inventory, &i.LedgerBalance); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertInventoryLedger = `-- name: InsertInventoryLedger :exec
+INSERT INTO
+    inventory_ledger (
+        product_id,
+        delta,
+        balance,
+        reason,
+        actor,
+        reference,
+        note
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type InsertInventoryLedgerParams struct {
+	ProductID int64
+	Delta     int32
+	Balance   int32
+	Reason    string
+	Actor     string
+	Reference string
+	Note      string
+}
+
+func (q *Queries) InsertInventoryLedger(ctx context.Context, arg InsertInventoryLedgerParams) error {
+	_, err := q.db.ExecContext(ctx, insertInventoryLedger,
+		arg.ProductID,
+		arg.Delta,
+		arg.Balance,
+		arg.Reason,
+		arg.Actor,
+		arg.Reference,
+		arg.Note,
+	)
+	return err
+}
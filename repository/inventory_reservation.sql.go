@@ -0,0 +1,298 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: inventory_reservation.sql
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const createReservation = `-- name: CreateReservation :one
+INSERT INTO
+    inventory_reservation (
+        product_id,
+        quantity,
+        expires_at
+    )
+VALUES ($1, $2, $3) RETURNING id, product_id, quantity, status, expires_at, created_at, order_id
+`
+
+type CreateReservationParams struct {
+	ProductID int64
+	Quantity  int32
+	ExpiresAt time.Time
+}
+
+func (q *Queries) CreateReservation(ctx context.Context, arg CreateReservationParams) (InventoryReservation, error) {
+	row := q.db.QueryRowContext(ctx, createReservation, arg.ProductID, arg.Quantity, arg.ExpiresAt)
+	var i InventoryReservation
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Quantity,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.OrderID,
+	)
+	return i, err
+}
+
+const expireProductReservations = `-- name: ExpireProductReservations :many
+UPDATE inventory_reservation
+SET status = 'expired'
+WHERE
+    product_id = $1
+    AND status = 'active'
+    AND expires_at <= now()
+RETURNING quantity
+`
+
+func (q *Queries) ExpireProductReservations(ctx context.Context, productID int64) ([]int32, error) {
+	rows, err := q.db.QueryContext(ctx, expireProductReservations, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var quantity int32
+		if err := rows.Scan(&quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, quantity)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExpiredReservationProducts = `-- name: GetExpiredReservationProducts :many
+SELECT DISTINCT product_id
+FROM inventory_reservation
+WHERE
+    status = 'active'
+    AND expires_at <= now()
+LIMIT $1
+`
+
+func (q *Queries) GetExpiredReservationProducts(ctx context.Context, limit int32) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getExpiredReservationProducts, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var product_id int64
+		if err := rows.Scan(&product_id); err != nil {
+			return nil, err
+		}
+		items = append(items, product_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInventoryAvailability = `-- name: GetInventoryAvailability :many
+SELECT
+    id,
+    inventory,
+    stock_policy,
+    backorder_limit,
+    preorder_until,
+    reserved
+FROM product
+WHERE id = ANY($1::bigint[])
+ORDER BY id
+`
+
+type GetInventoryAvailabilityRow struct {
+	ID             int64
+	Inventory      int32
+	StockPolicy    string
+	BackorderLimit int32
+	PreorderUntil  sql.NullTime
+	Reserved       int32
+}
+
+func (q *Queries) GetInventoryAvailability(ctx context.Context, ids []int64) ([]GetInventoryAvailabilityRow, error) {
+	rows, err := q.db.QueryContext(ctx, getInventoryAvailability, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetInventoryAvailabilityRow
+	for rows.Next() {
+		var i GetInventoryAvailabilityRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Inventory,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.Reserved,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReservationForUpdate = `-- name: GetReservationForUpdate :one
+SELECT id, product_id, quantity, status, expires_at, created_at, order_id FROM inventory_reservation WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetReservationForUpdate(ctx context.Context, id int64) (InventoryReservation, error) {
+	row := q.db.QueryRowContext(ctx, getReservationForUpdate, id)
+	var i InventoryReservation
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Quantity,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.OrderID,
+	)
+	return i, err
+}
+
+const getReservedInventory = `-- name: GetReservedInventory :one
+SELECT reserved FROM product WHERE id = $1
+`
+
+func (q *Queries) GetReservedInventory(ctx context.Context, id int64) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getReservedInventory, id)
+	var reserved int32
+	err := row.Scan(&reserved)
+	return reserved, err
+}
+
+const holdReservedInventory = `-- name: HoldReservedInventory :exec
+UPDATE product SET reserved = reserved + $1::integer WHERE id = $2
+`
+
+type HoldReservedInventoryParams struct {
+	Quantity int32
+	ID       int64
+}
+
+func (q *Queries) HoldReservedInventory(ctx context.Context, arg HoldReservedInventoryParams) error {
+	_, err := q.db.ExecContext(ctx, holdReservedInventory, arg.Quantity, arg.ID)
+	return err
+}
+
+const lockProductInventory = `-- name: LockProductInventory :one
+SELECT inventory FROM product WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) LockProductInventory(ctx context.Context, id int64) (int32, error) {
+	row := q.db.QueryRowContext(ctx, lockProductInventory, id)
+	var inventory int32
+	err := row.Scan(&inventory)
+	return inventory, err
+}
+
+const lockProductsInventory = `-- name: LockProductsInventory :many
+SELECT
+    id,
+    inventory,
+    reserved,
+    stock_policy,
+    backorder_limit,
+    preorder_until
+FROM product
+WHERE id = ANY($1::bigint[])
+ORDER BY id
+FOR UPDATE
+`
+
+type LockProductsInventoryRow struct {
+	ID             int64
+	Inventory      int32
+	Reserved       int32
+	StockPolicy    string
+	BackorderLimit int32
+	PreorderUntil  sql.NullTime
+}
+
+func (q *Queries) LockProductsInventory(ctx context.Context, ids []int64) ([]LockProductsInventoryRow, error) {
+	rows, err := q.db.QueryContext(ctx, lockProductsInventory, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LockProductsInventoryRow
+	for rows.Next() {
+		var i LockProductsInventoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Inventory,
+			&i.Reserved,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const releaseReservedInventory = `-- name: ReleaseReservedInventory :exec
+UPDATE product SET reserved = reserved - $1::integer WHERE id = $2
+`
+
+type ReleaseReservedInventoryParams struct {
+	Quantity int32
+	ID       int64
+}
+
+func (q *Queries) ReleaseReservedInventory(ctx context.Context, arg ReleaseReservedInventoryParams) error {
+	_, err := q.db.ExecContext(ctx, releaseReservedInventory, arg.Quantity, arg.ID)
+	return err
+}
+
+const updateReservationStatus = `-- name: UpdateReservationStatus :exec
+UPDATE inventory_reservation SET status = $2 WHERE id = $1
+`
+
+type UpdateReservationStatusParams struct {
+	ID     int64
+	Status string
+}
+
+func (q *Queries) UpdateReservationStatus(ctx context.Context, arg UpdateReservationStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateReservationStatus, arg.ID, arg.Status)
+	return err
+}
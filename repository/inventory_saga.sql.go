@@ -0,0 +1,154 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: inventory_saga.sql
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const claimInventorySaga = `-- name: ClaimInventorySaga :execrows
+INSERT INTO
+    inventory_saga (order_id, status)
+VALUES ($1, $2) ON CONFLICT (order_id) DO NOTHING
+`
+
+type ClaimInventorySagaParams struct {
+	OrderID int64
+	Status  string
+}
+
+func (q *Queries) ClaimInventorySaga(ctx context.Context, arg ClaimInventorySagaParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimInventorySaga, arg.OrderID, arg.Status)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createOrderReservation = `-- name: CreateOrderReservation :one
+INSERT INTO
+    inventory_reservation (
+        product_id,
+        quantity,
+        expires_at,
+        order_id
+    )
+VALUES ($1, $2, $3, $4) RETURNING id, product_id, quantity, status, expires_at, created_at, order_id
+`
+
+type CreateOrderReservationParams struct {
+	ProductID int64
+	Quantity  int32
+	ExpiresAt time.Time
+	OrderID   sql.NullInt64
+}
+
+func (q *Queries) CreateOrderReservation(ctx context.Context, arg CreateOrderReservationParams) (InventoryReservation, error) {
+	row := q.db.QueryRowContext(ctx, createOrderReservation,
+		arg.ProductID,
+		arg.Quantity,
+		arg.ExpiresAt,
+		arg.OrderID,
+	)
+	var i InventoryReservation
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Quantity,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.OrderID,
+	)
+	return i, err
+}
+
+const deleteOrderReservations = `-- name: DeleteOrderReservations :exec
+DELETE FROM inventory_reservation WHERE order_id = $1
+`
+
+func (q *Queries) DeleteOrderReservations(ctx context.Context, orderID sql.NullInt64) error {
+	_, err := q.db.ExecContext(ctx, deleteOrderReservations, orderID)
+	return err
+}
+
+const getInventorySagaForUpdate = `-- name: GetInventorySagaForUpdate :one
+SELECT order_id, status, expires_at, created_at, updated_at FROM inventory_saga WHERE order_id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetInventorySagaForUpdate(ctx context.Context, orderID int64) (InventorySaga, error) {
+	row := q.db.QueryRowContext(ctx, getInventorySagaForUpdate, orderID)
+	var i InventorySaga
+	err := row.Scan(
+		&i.OrderID,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOrderReservationsForUpdate = `-- name: GetOrderReservationsForUpdate :many
+SELECT id, product_id, quantity, status, expires_at, created_at, order_id
+FROM inventory_reservation
+WHERE order_id = $1
+ORDER BY product_id
+FOR UPDATE
+`
+
+func (q *Queries) GetOrderReservationsForUpdate(ctx context.Context, orderID sql.NullInt64) ([]InventoryReservation, error) {
+	rows, err := q.db.QueryContext(ctx, getOrderReservationsForUpdate, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InventoryReservation
+	for rows.Next() {
+		var i InventoryReservation
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Quantity,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.OrderID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateInventorySaga = `-- name: UpdateInventorySaga :exec
+UPDATE inventory_saga
+SET
+    status = $2,
+    expires_at = $3,
+    updated_at = now()
+WHERE order_id = $1
+`
+
+type UpdateInventorySagaParams struct {
+	OrderID   int64
+	Status    string
+	ExpiresAt sql.NullTime
+}
+
+func (q *Queries) UpdateInventorySaga(ctx context.Context, arg UpdateInventorySagaParams) error {
+	_, err := q.db.ExecContext(ctx, updateInventorySaga, arg.OrderID, arg.Status, arg.ExpiresAt)
+	return err
+}
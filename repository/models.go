@@ -6,6 +6,7 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
@@ -16,15 +17,195 @@ type Category struct {
 	Thumbnail sql.NullString
 }
 
+type CustomerActivity struct {
+	CustomerID   int64
+	ProductID    int64
+	ActivityType string
+	Hit          int32
+	UpdatedAt    time.Time
+}
+
+type FlashSale struct {
+	ID               int64
+	ProductID        int64
+	SupplierID       int64
+	SalePrice        int64
+	StartsAt         time.Time
+	EndsAt           time.Time
+	Allocated        int32
+	Remaining        int32
+	PerCustomerLimit int32
+	LocationID       int64
+	Status           string
+	CreatedAt        time.Time
+	Held             bool
+}
+
+type FlashSalePurchase struct {
+	FlashSaleID int64
+	CustomerID  int64
+	Quantity    int32
+}
+
+type InventoryIdempotency struct {
+	Key         string
+	Method      string
+	Response    []byte
+	ErrorStatus []byte
+	CreatedAt   time.Time
+	RequestHash []byte
+}
+
+type InventoryLedger struct {
+	ID        int64
+	ProductID int64
+	Delta     int32
+	Balance   int32
+	Reason    string
+	Actor     string
+	Reference string
+	CreatedAt time.Time
+	Note      string
+}
+
+type InventoryReservation struct {
+	ID        int64
+	ProductID int64
+	Quantity  int32
+	Status    string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	OrderID   sql.NullInt64
+}
+
+type InventorySaga struct {
+	OrderID   int64
+	Status    string
+	ExpiresAt sql.NullTime
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
 type Product struct {
+	ID               int64
+	Name             string
+	Description      string
+	Price            int64
+	Thumbnail        string
+	Inventory        int32
+	SupplierID       int64
+	CategoryID       int64
+	CreatedAt        time.Time
+	Brand            sql.NullString
+	ReorderThreshold int32
+	StockPolicy      string
+	BackorderLimit   int32
+	PreorderUntil    sql.NullTime
+	AvailableAt      sql.NullTime
+	Reserved         int32
+}
+
+type ProductApproval struct {
+	ProductID  int64
+	ApprovedAt time.Time
+}
+
+type ProductEvent struct {
 	ID          int64
-	Name        string
-	Description string
-	Price       int64
-	Thumbnail   string
-	Inventory   int32
+	ProductID   int64
+	EventType   string
+	Payload     []byte
+	PublishedAt sql.NullTime
+	CreatedAt   time.Time
 	SupplierID  int64
 	CategoryID  int64
-	CreatedAt   time.Time
-	Brand       sql.NullString
+	Txid        int64
+	Sequence    sql.NullInt64
+}
+
+type ProductRelation struct {
+	ProductID        int64
+	RelatedProductID int64
+	RelationType     string
+	CreatedAt        time.Time
+}
+
+type ProductSearch struct {
+	ProductID int64
+	Document  interface{}
+	Folded    string
+}
+
+type ProductStock struct {
+	ProductID  int64
+	LocationID int64
+	Quantity   int32
+}
+
+type ProductTextVector struct {
+	ProductID int64
+	Terms     json.RawMessage
+	UpdatedAt time.Time
+}
+
+type SearchOutbox struct {
+	ID            int64
+	ProductID     int64
+	Operation     string
+	Attempts      int32
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	Status        string
+}
+
+type SearchReindex struct {
+	ID            int64
+	LastProductID int64
+	Indexed       int64
+	Status        string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type StockAlert struct {
+	ID         int64
+	ProductID  int64
+	SupplierID int64
+	AlertType  string
+	Inventory  int32
+	Threshold  int32
+	CreatedAt  time.Time
+	Txid       int64
+}
+
+type StockLocation struct {
+	ID         int64
+	SupplierID int64
+	Name       string
+	Priority   int32
+	CreatedAt  time.Time
+}
+
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	EventType      string
+	Payload        string
+	Status         string
+	Attempts       int32
+	ResponseStatus int32
+	LastError      string
+	NextAttemptAt  time.Time
+	DeliveredAt    sql.NullTime
+	CreatedAt      time.Time
+}
+
+type WebhookSubscription struct {
+	ID         int64
+	SupplierID int64
+	Url        string
+	EventTypes []string
+	Secret     string
+	CreatedAt  time.Time
 }
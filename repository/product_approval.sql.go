@@ -0,0 +1,22 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: product_approval.sql
+
+package repository
+
+import (
+	"context"
+)
+
+const approveProduct = `-- name: ApproveProduct :execrows
+INSERT INTO product_approval (product_id) VALUES ($1) ON CONFLICT DO NOTHING
+`
+
+func (q *Queries) ApproveProduct(ctx context.Context, productID int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, approveProduct, productID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
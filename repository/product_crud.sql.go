@@ -8,6 +8,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const createProduct = `-- name: CreateProduct :one
@@ -23,7 +25,7 @@ INSERT INTO
         category_id,
         brand
     )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved
 `
 
 type CreateProductParams struct {
@@ -60,11 +62,17 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.CategoryID,
 		&i.CreatedAt,
 		&i.Brand,
+		&i.ReorderThreshold,
+		&i.StockPolicy,
+		&i.BackorderLimit,
+		&i.PreorderUntil,
+		&i.AvailableAt,
+		&i.Reserved,
 	)
 	return i, err
 }
 
-const deleteProduct = `-- name: DeleteProduct :exec
+const deleteProduct = `-- name: DeleteProduct :execrows
 DELETE FROM product WHERE id = $1 and supplier_id = $2
 `
 
@@ -73,25 +81,39 @@ type DeleteProductParams struct {
 	SupplierID int64
 }
 
-func (q *Queries) DeleteProduct(ctx context.Context, arg DeleteProductParams) error {
-	_, err := q.db.ExecContext(ctx, deleteProduct, arg.ID, arg.SupplierID)
-	return err
+func (q *Queries) DeleteProduct(ctx context.Context, arg DeleteProductParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteProduct, arg.ID, arg.SupplierID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const deleteProductByID = `-- name: DeleteProductByID :exec
+const deleteProductByID = `-- name: DeleteProductByID :execrows
 DELETE FROM product WHERE id = $1
 `
 
-func (q *Queries) DeleteProductByID(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteProductByID, id)
-	return err
+func (q *Queries) DeleteProductByID(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteProductByID, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const descInventory = `-- name: DescInventory :exec
+const descInventory = `-- name: DescInventory :one
 UPDATE product
 SET
     inventory = inventory - $1
-WHERE id = $2 and inventory >= $1
+WHERE product.id = $2 and inventory - reserved + (
+        CASE
+            WHEN stock_policy = 'backorder' THEN backorder_limit
+            WHEN stock_policy = 'preorder'
+            AND preorder_until > now() THEN 2147483647
+            ELSE 0
+        END
+    ) >= $1
+RETURNING inventory
 `
 
 type DescInventoryParams struct {
@@ -99,14 +121,16 @@ type DescInventoryParams struct {
 	ID        int64
 }
 
-func (q *Queries) DescInventory(ctx context.Context, arg DescInventoryParams) error {
-	_, err := q.db.ExecContext(ctx, descInventory, arg.Inventory, arg.ID)
-	return err
+func (q *Queries) DescInventory(ctx context.Context, arg DescInventoryParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, descInventory, arg.Inventory, arg.ID)
+	var inventory int32
+	err := row.Scan(&inventory)
+	return inventory, err
 }
 
 const getAllProduct = `-- name: GetAllProduct :many
 
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product
 `
 
 func (q *Queries) GetAllProduct(ctx context.Context) ([]Product, error) {
@@ -129,6 +153,12 @@ func (q *Queries) GetAllProduct(ctx context.Context) ([]Product, error) {
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -145,7 +175,7 @@ func (q *Queries) GetAllProduct(ctx context.Context) ([]Product, error) {
 
 const getListProductByIDs = `-- name: GetListProductByIDs :many
 
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE id IN ($1)
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE id IN ($1)
 `
 
 func (q *Queries) GetListProductByIDs(ctx context.Context, id string) ([]Product, error) {
@@ -168,6 +198,12 @@ func (q *Queries) GetListProductByIDs(ctx context.Context, id string) ([]Product
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -184,7 +220,7 @@ func (q *Queries) GetListProductByIDs(ctx context.Context, id string) ([]Product
 
 const getProductByCategory = `-- name: GetProductByCategory :many
 
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE category_id = $1 LIMIT $2 OFFSET $3
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE category_id = $1 LIMIT $2 OFFSET $3
 `
 
 type GetProductByCategoryParams struct {
@@ -213,6 +249,12 @@ func (q *Queries) GetProductByCategory(ctx context.Context, arg GetProductByCate
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -228,7 +270,7 @@ func (q *Queries) GetProductByCategory(ctx context.Context, arg GetProductByCate
 }
 
 const getProductByCategoryAndPriceDesc = `-- name: GetProductByCategoryAndPriceDesc :many
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE category_id = $1 ORDER BY price DESC LIMIT $2 OFFSET $3
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE category_id = $1 ORDER BY price DESC LIMIT $2 OFFSET $3
 `
 
 type GetProductByCategoryAndPriceDescParams struct {
@@ -257,6 +299,12 @@ func (q *Queries) GetProductByCategoryAndPriceDesc(ctx context.Context, arg GetP
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -272,7 +320,7 @@ func (q *Queries) GetProductByCategoryAndPriceDesc(ctx context.Context, arg GetP
 }
 
 const getProductByCategoryAndPriceInc = `-- name: GetProductByCategoryAndPriceInc :many
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE category_id = $1 ORDER BY price LIMIT $2 OFFSET $3
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE category_id = $1 ORDER BY price LIMIT $2 OFFSET $3
 `
 
 type GetProductByCategoryAndPriceIncParams struct {
@@ -301,6 +349,12 @@ func (q *Queries) GetProductByCategoryAndPriceInc(ctx context.Context, arg GetPr
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -316,7 +370,7 @@ func (q *Queries) GetProductByCategoryAndPriceInc(ctx context.Context, arg GetPr
 }
 
 const getProductByCategoryAndTime = `-- name: GetProductByCategoryAndTime :many
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE category_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE category_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3
 `
 
 type GetProductByCategoryAndTimeParams struct {
@@ -345,6 +399,12 @@ func (q *Queries) GetProductByCategoryAndTime(ctx context.Context, arg GetProduc
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -361,7 +421,7 @@ func (q *Queries) GetProductByCategoryAndTime(ctx context.Context, arg GetProduc
 
 const getProductByID = `-- name: GetProductByID :one
 
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE id = $1
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE id = $1
 `
 
 func (q *Queries) GetProductByID(ctx context.Context, id int64) (Product, error) {
@@ -378,13 +438,19 @@ func (q *Queries) GetProductByID(ctx context.Context, id int64) (Product, error)
 		&i.CategoryID,
 		&i.CreatedAt,
 		&i.Brand,
+		&i.ReorderThreshold,
+		&i.StockPolicy,
+		&i.BackorderLimit,
+		&i.PreorderUntil,
+		&i.AvailableAt,
+		&i.Reserved,
 	)
 	return i, err
 }
 
 const getProductBySupplier = `-- name: GetProductBySupplier :many
 
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE supplier_id = $1 LIMIT $2 OFFSET $3
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE supplier_id = $1 LIMIT $2 OFFSET $3
 `
 
 type GetProductBySupplierParams struct {
@@ -413,6 +479,12 @@ func (q *Queries) GetProductBySupplier(ctx context.Context, arg GetProductBySupp
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -429,7 +501,7 @@ func (q *Queries) GetProductBySupplier(ctx context.Context, arg GetProductBySupp
 
 const getProductBySupplierAndCategory = `-- name: GetProductBySupplierAndCategory :many
 
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE supplier_id = $1 AND category_id = $4 LIMIT $2 OFFSET $3
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE supplier_id = $1 AND category_id = $4 LIMIT $2 OFFSET $3
 `
 
 type GetProductBySupplierAndCategoryParams struct {
@@ -464,6 +536,12 @@ func (q *Queries) GetProductBySupplierAndCategory(ctx context.Context, arg GetPr
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -480,7 +558,7 @@ func (q *Queries) GetProductBySupplierAndCategory(ctx context.Context, arg GetPr
 
 const getProductBySupplierAndPriceDesc = `-- name: GetProductBySupplierAndPriceDesc :many
 
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE supplier_id = $1 ORDER BY price DESC LIMIT $2 OFFSET $3
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE supplier_id = $1 ORDER BY price DESC LIMIT $2 OFFSET $3
 `
 
 type GetProductBySupplierAndPriceDescParams struct {
@@ -509,6 +587,12 @@ func (q *Queries) GetProductBySupplierAndPriceDesc(ctx context.Context, arg GetP
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -525,7 +609,7 @@ func (q *Queries) GetProductBySupplierAndPriceDesc(ctx context.Context, arg GetP
 
 const getProductBySupplierAndPriceDescAndCategory = `-- name: GetProductBySupplierAndPriceDescAndCategory :many
 
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE supplier_id = $1 AND category_id = $4 ORDER BY price DESC LIMIT $2 OFFSET $3
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE supplier_id = $1 AND category_id = $4 ORDER BY price DESC LIMIT $2 OFFSET $3
 `
 
 type GetProductBySupplierAndPriceDescAndCategoryParams struct {
@@ -560,6 +644,12 @@ func (q *Queries) GetProductBySupplierAndPriceDescAndCategory(ctx context.Contex
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -576,7 +666,7 @@ func (q *Queries) GetProductBySupplierAndPriceDescAndCategory(ctx context.Contex
 
 const getProductBySupplierAndPriceInc = `-- name: GetProductBySupplierAndPriceInc :many
 
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE supplier_id = $1 ORDER BY price LIMIT $2 OFFSET $3
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE supplier_id = $1 ORDER BY price LIMIT $2 OFFSET $3
 `
 
 type GetProductBySupplierAndPriceIncParams struct {
@@ -605,6 +695,12 @@ func (q *Queries) GetProductBySupplierAndPriceInc(ctx context.Context, arg GetPr
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -621,7 +717,7 @@ func (q *Queries) GetProductBySupplierAndPriceInc(ctx context.Context, arg GetPr
 
 const getProductBySupplierAndPriceIncAndCategory = `-- name: GetProductBySupplierAndPriceIncAndCategory :many
 
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE supplier_id = $1 AND category_id=$4 ORDER BY price LIMIT $2 OFFSET $3
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE supplier_id = $1 AND category_id=$4 ORDER BY price LIMIT $2 OFFSET $3
 `
 
 type GetProductBySupplierAndPriceIncAndCategoryParams struct {
@@ -656,6 +752,12 @@ func (q *Queries) GetProductBySupplierAndPriceIncAndCategory(ctx context.Context
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -672,7 +774,7 @@ func (q *Queries) GetProductBySupplierAndPriceIncAndCategory(ctx context.Context
 
 const getProductBySupplierAndTime = `-- name: GetProductBySupplierAndTime :many
 
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE supplier_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE supplier_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3
 `
 
 type GetProductBySupplierAndTimeParams struct {
@@ -701,6 +803,12 @@ func (q *Queries) GetProductBySupplierAndTime(ctx context.Context, arg GetProduc
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -717,7 +825,7 @@ func (q *Queries) GetProductBySupplierAndTime(ctx context.Context, arg GetProduc
 
 const getProductBySupplierAndTimeAndCategory = `-- name: GetProductBySupplierAndTimeAndCategory :many
 
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE supplier_id = $1 AND category_id = $4 ORDER BY created_at DESC LIMIT $2 OFFSET $3
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE supplier_id = $1 AND category_id = $4 ORDER BY created_at DESC LIMIT $2 OFFSET $3
 `
 
 type GetProductBySupplierAndTimeAndCategoryParams struct {
@@ -752,6 +860,12 @@ func (q *Queries) GetProductBySupplierAndTimeAndCategory(ctx context.Context, ar
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -778,9 +892,53 @@ func (q *Queries) GetProductInventory(ctx context.Context, id int64) (int32, err
 	return inventory, err
 }
 
+const getProductsByIDs = `-- name: GetProductsByIDs :many
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE id = ANY($1::bigint[])
+`
+
+func (q *Queries) GetProductsByIDs(ctx context.Context, ids []int64) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, getProductsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.Thumbnail,
+			&i.Inventory,
+			&i.SupplierID,
+			&i.CategoryID,
+			&i.CreatedAt,
+			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRecommendProduct = `-- name: GetRecommendProduct :many
 
-SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product LIMIT $1 OFFSET $2
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product LIMIT $1 OFFSET $2
 `
 
 type GetRecommendProductParams struct {
@@ -808,6 +966,12 @@ func (q *Queries) GetRecommendProduct(ctx context.Context, arg GetRecommendProdu
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
 		); err != nil {
 			return nil, err
 		}
@@ -822,11 +986,12 @@ func (q *Queries) GetRecommendProduct(ctx context.Context, arg GetRecommendProdu
 	return items, nil
 }
 
-const incInventory = `-- name: IncInventory :exec
+const incInventory = `-- name: IncInventory :one
 UPDATE product
 SET
     inventory = inventory + $1
 WHERE id = $2
+RETURNING inventory
 `
 
 type IncInventoryParams struct {
@@ -834,12 +999,14 @@ type IncInventoryParams struct {
 	ID        int64
 }
 
-func (q *Queries) IncInventory(ctx context.Context, arg IncInventoryParams) error {
-	_, err := q.db.ExecContext(ctx, incInventory, arg.Inventory, arg.ID)
-	return err
+func (q *Queries) IncInventory(ctx context.Context, arg IncInventoryParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, incInventory, arg.Inventory, arg.ID)
+	var inventory int32
+	err := row.Scan(&inventory)
+	return inventory, err
 }
 
-const updateProduct = `-- name: UpdateProduct :exec
+const updateProduct = `-- name: UpdateProduct :execrows
 
 UPDATE product
 SET
@@ -859,8 +1026,8 @@ type UpdateProductParams struct {
 	SupplierID int64
 }
 
-func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) error {
-	_, err := q.db.ExecContext(ctx, updateProduct,
+func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateProduct,
 		arg.ID,
 		arg.Name,
 		arg.Price,
@@ -868,5 +1035,8 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) er
 		arg.Brand,
 		arg.SupplierID,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
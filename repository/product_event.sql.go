@@ -0,0 +1,239 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: product_event.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+const assignProductEventSequences = `-- name: AssignProductEventSequences :execrows
+UPDATE product_event
+SET sequence = numbered.sequence
+FROM (
+        SELECT
+            id,
+            (
+                SELECT coalesce(max(sequence), 0)
+                FROM product_event
+            ) + row_number() OVER (
+                ORDER BY txid, id
+            ) AS sequence
+        FROM product_event
+        WHERE
+            sequence IS NULL
+            AND txid < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+    ) AS numbered
+WHERE product_event.id = numbered.id
+`
+
+func (q *Queries) AssignProductEventSequences(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, assignProductEventSequences)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const enqueueProductEvent = `-- name: EnqueueProductEvent :exec
+INSERT INTO
+    product_event (
+        product_id,
+        supplier_id,
+        category_id,
+        event_type,
+        payload
+    )
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type EnqueueProductEventParams struct {
+	ProductID  int64
+	SupplierID int64
+	CategoryID int64
+	EventType  string
+	Payload    []byte
+}
+
+func (q *Queries) EnqueueProductEvent(ctx context.Context, arg EnqueueProductEventParams) error {
+	_, err := q.db.ExecContext(ctx, enqueueProductEvent,
+		arg.ProductID,
+		arg.SupplierID,
+		arg.CategoryID,
+		arg.EventType,
+		arg.Payload,
+	)
+	return err
+}
+
+const getLastProductEventSequence = `-- name: GetLastProductEventSequence :one
+SELECT coalesce(max(sequence), 0)::bigint AS sequence FROM product_event
+`
+
+func (q *Queries) GetLastProductEventSequence(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getLastProductEventSequence)
+	var sequence int64
+	err := row.Scan(&sequence)
+	return sequence, err
+}
+
+const getProductEventLag = `-- name: GetProductEventLag :one
+SELECT
+    count(*) AS pending,
+    coalesce(
+        extract(
+            epoch
+            FROM
+                now() - min(created_at)
+        ),
+        0
+    )::float8 AS lag_seconds
+FROM product_event
+WHERE published_at IS NULL
+`
+
+type GetProductEventLagRow struct {
+	Pending    int64
+	LagSeconds float64
+}
+
+func (q *Queries) GetProductEventLag(ctx context.Context) (GetProductEventLagRow, error) {
+	row := q.db.QueryRowContext(ctx, getProductEventLag)
+	var i GetProductEventLagRow
+	err := row.Scan(&i.Pending, &i.LagSeconds)
+	return i, err
+}
+
+const getProductEventsAfter = `-- name: GetProductEventsAfter :many
+SELECT id, product_id, event_type, payload, published_at, created_at, supplier_id, category_id, txid, sequence
+FROM product_event
+WHERE
+    sequence > $1::bigint
+    AND sequence <= $2::bigint
+    AND ($3::bigint = 0 OR supplier_id = $3)
+    AND ($4::bigint = 0 OR category_id = $4)
+    AND (
+        coalesce(cardinality($5::bigint[]), 0) = 0
+        OR product_id = ANY($5::bigint[])
+    )
+ORDER BY sequence
+LIMIT $6
+`
+
+type GetProductEventsAfterParams struct {
+	AfterSequence int64
+	LastSequence  int64
+	SupplierID    int64
+	CategoryID    int64
+	ProductIds    []int64
+	RowLimit      int32
+}
+
+func (q *Queries) GetProductEventsAfter(ctx context.Context, arg GetProductEventsAfterParams) ([]ProductEvent, error) {
+	rows, err := q.db.QueryContext(ctx, getProductEventsAfter,
+		arg.AfterSequence,
+		arg.LastSequence,
+		arg.SupplierID,
+		arg.CategoryID,
+		pq.Array(arg.ProductIds),
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductEvent
+	for rows.Next() {
+		var i ProductEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.EventType,
+			&i.Payload,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.SupplierID,
+			&i.CategoryID,
+			&i.Txid,
+			&i.Sequence,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnpublishedProductEvents = `-- name: GetUnpublishedProductEvents :many
+SELECT id, product_id, event_type, payload, published_at, created_at, supplier_id, category_id, txid, sequence
+FROM product_event
+WHERE published_at IS NULL AND sequence IS NOT NULL
+ORDER BY sequence
+LIMIT $1
+`
+
+func (q *Queries) GetUnpublishedProductEvents(ctx context.Context, limit int32) ([]ProductEvent, error) {
+	rows, err := q.db.QueryContext(ctx, getUnpublishedProductEvents, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductEvent
+	for rows.Next() {
+		var i ProductEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.EventType,
+			&i.Payload,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.SupplierID,
+			&i.CategoryID,
+			&i.Txid,
+			&i.Sequence,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markProductEventsPublished = `-- name: MarkProductEventsPublished :exec
+UPDATE product_event
+SET published_at = now()
+WHERE sequence <= $1::bigint AND published_at IS NULL
+`
+
+func (q *Queries) MarkProductEventsPublished(ctx context.Context, lastSequence int64) error {
+	_, err := q.db.ExecContext(ctx, markProductEventsPublished, lastSequence)
+	return err
+}
+
+const tryLockProductEventRelay = `-- name: TryLockProductEventRelay :one
+SELECT pg_try_advisory_xact_lock(hashtext('product_event_relay'))::bool AS locked
+`
+
+func (q *Queries) TryLockProductEventRelay(ctx context.Context) (bool, error) {
+	row := q.db.QueryRowContext(ctx, tryLockProductEventRelay)
+	var locked bool
+	err := row.Scan(&locked)
+	return locked, err
+}
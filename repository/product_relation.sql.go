@@ -0,0 +1,153 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: product_relation.sql
+
+package repository
+
+import (
+	"context"
+)
+
+const createProductRelation = `-- name: CreateProductRelation :exec
+INSERT INTO
+    product_relation (
+        product_id,
+        related_product_id,
+        relation_type
+    )
+VALUES ($1, $2, $3) ON CONFLICT DO NOTHING
+`
+
+type CreateProductRelationParams struct {
+	ProductID        int64
+	RelatedProductID int64
+	RelationType     string
+}
+
+func (q *Queries) CreateProductRelation(ctx context.Context, arg CreateProductRelationParams) error {
+	_, err := q.db.ExecContext(ctx, createProductRelation, arg.ProductID, arg.RelatedProductID, arg.RelationType)
+	return err
+}
+
+const deleteProductRelation = `-- name: DeleteProductRelation :execrows
+DELETE FROM product_relation
+WHERE
+    product_id = $1
+    AND related_product_id = $2
+    AND relation_type = $3
+`
+
+type DeleteProductRelationParams struct {
+	ProductID        int64
+	RelatedProductID int64
+	RelationType     string
+}
+
+func (q *Queries) DeleteProductRelation(ctx context.Context, arg DeleteProductRelationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteProductRelation, arg.ProductID, arg.RelatedProductID, arg.RelationType)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getProductRelations = `-- name: GetProductRelations :many
+SELECT related_product_id, relation_type
+FROM product_relation
+WHERE product_id = $1
+ORDER BY relation_type, created_at
+`
+
+type GetProductRelationsRow struct {
+	RelatedProductID int64
+	RelationType     string
+}
+
+func (q *Queries) GetProductRelations(ctx context.Context, productID int64) ([]GetProductRelationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProductRelations, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProductRelationsRow
+	for rows.Next() {
+		var i GetProductRelationsRow
+		if err := rows.Scan(&i.RelatedProductID, &i.RelationType); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSimilarProductsByCategory = `-- name: GetSimilarProductsByCategory :many
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved
+FROM product
+WHERE
+    category_id = $1
+    AND id <> $2
+    AND inventory > 0
+ORDER BY (coalesce(brand, '') = $3::text) DESC, abs(price - $4)
+LIMIT $5
+`
+
+type GetSimilarProductsByCategoryParams struct {
+	CategoryID int64
+	ProductID  int64
+	Brand      string
+	Price      int64
+	Size       int32
+}
+
+func (q *Queries) GetSimilarProductsByCategory(ctx context.Context, arg GetSimilarProductsByCategoryParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, getSimilarProductsByCategory,
+		arg.CategoryID,
+		arg.ProductID,
+		arg.Brand,
+		arg.Price,
+		arg.Size,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.Thumbnail,
+			&i.Inventory,
+			&i.SupplierID,
+			&i.CategoryID,
+			&i.CreatedAt,
+			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,135 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: product_search.sql
+
+package repository
+
+import (
+	"context"
+)
+
+const countSearchProducts = `-- name: CountSearchProducts :one
+SELECT count(*)
+FROM product
+    JOIN product_search ON product_search.product_id = product.id
+WHERE
+    (
+        product_search.document @@ websearch_to_tsquery('simple', fold_vietnamese($1::text))
+        OR fold_vietnamese($1::text) <% product_search.folded
+    )
+    AND ($2::bigint = 0 OR product.category_id = $2)
+    AND ($3::bigint = 0 OR product.supplier_id = $3)
+    AND ($4::text = '' OR coalesce(product.brand, '') = $4)
+    AND ($5::bigint = 0 OR product.price >= $5)
+    AND ($6::bigint = 0 OR product.price <= $6)
+`
+
+type CountSearchProductsParams struct {
+	Query      string
+	CategoryID int64
+	SupplierID int64
+	Brand      string
+	MinPrice   int64
+	MaxPrice   int64
+}
+
+func (q *Queries) CountSearchProducts(ctx context.Context, arg CountSearchProductsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchProducts,
+		arg.Query,
+		arg.CategoryID,
+		arg.SupplierID,
+		arg.Brand,
+		arg.MinPrice,
+		arg.MaxPrice,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const searchProducts = `-- name: SearchProducts :many
+SELECT product.id, product.name, product.description, product.price, product.thumbnail, product.inventory, product.supplier_id, product.category_id, product.created_at, product.brand, product.reorder_threshold, product.stock_policy, product.backorder_limit, product.preorder_until, product.available_at, product.reserved
+FROM product
+    JOIN product_search ON product_search.product_id = product.id
+WHERE
+    (
+        product_search.document @@ websearch_to_tsquery('simple', fold_vietnamese($1::text))
+        OR fold_vietnamese($1::text) <% product_search.folded
+    )
+    AND ($2::bigint = 0 OR product.category_id = $2)
+    AND ($3::bigint = 0 OR product.supplier_id = $3)
+    AND ($4::text = '' OR coalesce(product.brand, '') = $4)
+    AND ($5::bigint = 0 OR product.price >= $5)
+    AND ($6::bigint = 0 OR product.price <= $6)
+ORDER BY
+    CASE WHEN $7::text = 'time' THEN product.created_at END DESC,
+    CASE WHEN $7::text = 'price_inc' THEN product.price END ASC,
+    CASE WHEN $7::text = 'price_desc' THEN product.price END DESC,
+    ts_rank(product_search.document, websearch_to_tsquery('simple', fold_vietnamese($1::text))) + word_similarity(fold_vietnamese($1::text), product_search.folded) DESC,
+    product.id
+LIMIT $9
+OFFSET $8
+`
+
+type SearchProductsParams struct {
+	Query      string
+	CategoryID int64
+	SupplierID int64
+	Brand      string
+	MinPrice   int64
+	MaxPrice   int64
+	Sort       string
+	RowOffset  int32
+	RowLimit   int32
+}
+
+func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, searchProducts,
+		arg.Query,
+		arg.CategoryID,
+		arg.SupplierID,
+		arg.Brand,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.Sort,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.Thumbnail,
+			&i.Inventory,
+			&i.SupplierID,
+			&i.CategoryID,
+			&i.CreatedAt,
+			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,149 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: product_text_vector.sql
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+const getAllProductTextVector = `-- name: GetAllProductTextVector :many
+SELECT product_id, terms FROM product_text_vector
+`
+
+type GetAllProductTextVectorRow struct {
+	ProductID int64
+	Terms     json.RawMessage
+}
+
+func (q *Queries) GetAllProductTextVector(ctx context.Context) ([]GetAllProductTextVectorRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllProductTextVector)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllProductTextVectorRow
+	for rows.Next() {
+		var i GetAllProductTextVectorRow
+		if err := rows.Scan(&i.ProductID, &i.Terms); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProductDocument = `-- name: GetProductDocument :one
+SELECT
+    product.id,
+    product.name,
+    product.description,
+    product.brand,
+    category.name AS category_name
+FROM product
+    JOIN category ON category.id = product.category_id
+WHERE product.id = $1
+`
+
+type GetProductDocumentRow struct {
+	ID           int64
+	Name         string
+	Description  string
+	Brand        sql.NullString
+	CategoryName string
+}
+
+func (q *Queries) GetProductDocument(ctx context.Context, id int64) (GetProductDocumentRow, error) {
+	row := q.db.QueryRowContext(ctx, getProductDocument, id)
+	var i GetProductDocumentRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Brand,
+		&i.CategoryName,
+	)
+	return i, err
+}
+
+const getProductDocumentsWithoutVector = `-- name: GetProductDocumentsWithoutVector :many
+SELECT
+    product.id,
+    product.name,
+    product.description,
+    product.brand,
+    category.name AS category_name
+FROM product
+    JOIN category ON category.id = product.category_id
+WHERE product.id NOT IN (
+        SELECT product_id
+        FROM product_text_vector
+    )
+`
+
+type GetProductDocumentsWithoutVectorRow struct {
+	ID           int64
+	Name         string
+	Description  string
+	Brand        sql.NullString
+	CategoryName string
+}
+
+func (q *Queries) GetProductDocumentsWithoutVector(ctx context.Context) ([]GetProductDocumentsWithoutVectorRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProductDocumentsWithoutVector)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProductDocumentsWithoutVectorRow
+	for rows.Next() {
+		var i GetProductDocumentsWithoutVectorRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Brand,
+			&i.CategoryName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertProductTextVector = `-- name: UpsertProductTextVector :exec
+INSERT INTO
+    product_text_vector (product_id, terms)
+VALUES ($1, $2) ON CONFLICT (product_id) DO
+UPDATE
+SET
+    terms = EXCLUDED.terms,
+    updated_at = now()
+`
+
+type UpsertProductTextVectorParams struct {
+	ProductID int64
+	Terms     json.RawMessage
+}
+
+func (q *Queries) UpsertProductTextVector(ctx context.Context, arg UpsertProductTextVectorParams) error {
+	_, err := q.db.ExecContext(ctx, upsertProductTextVector, arg.ProductID, arg.Terms)
+	return err
+}
@@ -0,0 +1,142 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: search_outbox.sql
+
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+const claimSearchOutbox = `-- name: ClaimSearchOutbox :many
+UPDATE search_outbox
+SET next_attempt_at = $1
+WHERE id IN (
+        SELECT id
+        FROM search_outbox
+        WHERE
+            status = 'pending'
+            AND next_attempt_at <= now()
+        ORDER BY id
+        LIMIT $2
+        FOR UPDATE SKIP LOCKED
+    )
+RETURNING id, product_id, operation, attempts, last_error, next_attempt_at, created_at, status
+`
+
+type ClaimSearchOutboxParams struct {
+	LeaseUntil time.Time
+	RowLimit   int32
+}
+
+func (q *Queries) ClaimSearchOutbox(ctx context.Context, arg ClaimSearchOutboxParams) ([]SearchOutbox, error) {
+	rows, err := q.db.QueryContext(ctx, claimSearchOutbox, arg.LeaseUntil, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchOutbox
+	for rows.Next() {
+		var i SearchOutbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Operation,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.CreatedAt,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteSearchOutbox = `-- name: DeleteSearchOutbox :exec
+DELETE FROM search_outbox WHERE id = $1
+`
+
+func (q *Queries) DeleteSearchOutbox(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSearchOutbox, id)
+	return err
+}
+
+const enqueueSearchOutbox = `-- name: EnqueueSearchOutbox :exec
+INSERT INTO search_outbox (product_id, operation) VALUES ($1, $2)
+`
+
+type EnqueueSearchOutboxParams struct {
+	ProductID int64
+	Operation string
+}
+
+func (q *Queries) EnqueueSearchOutbox(ctx context.Context, arg EnqueueSearchOutboxParams) error {
+	_, err := q.db.ExecContext(ctx, enqueueSearchOutbox, arg.ProductID, arg.Operation)
+	return err
+}
+
+const getSearchOutboxLag = `-- name: GetSearchOutboxLag :one
+SELECT
+    count(*) FILTER (WHERE status = 'pending') AS pending,
+    count(*) FILTER (WHERE status = 'dead') AS dead,
+    coalesce(
+        extract(
+            epoch
+            FROM
+                now() - min(created_at) FILTER (WHERE status = 'pending')
+        ),
+        0
+    )::float8 AS lag_seconds
+FROM search_outbox
+`
+
+type GetSearchOutboxLagRow struct {
+	Pending    int64
+	Dead       int64
+	LagSeconds float64
+}
+
+func (q *Queries) GetSearchOutboxLag(ctx context.Context) (GetSearchOutboxLagRow, error) {
+	row := q.db.QueryRowContext(ctx, getSearchOutboxLag)
+	var i GetSearchOutboxLagRow
+	err := row.Scan(&i.Pending, &i.Dead, &i.LagSeconds)
+	return i, err
+}
+
+const retrySearchOutbox = `-- name: RetrySearchOutbox :exec
+UPDATE search_outbox
+SET
+    status = $1,
+    attempts = attempts + 1,
+    last_error = $2,
+    next_attempt_at = $3
+WHERE id = $4
+`
+
+type RetrySearchOutboxParams struct {
+	Status        string
+	LastError     string
+	NextAttemptAt time.Time
+	ID            int64
+}
+
+func (q *Queries) RetrySearchOutbox(ctx context.Context, arg RetrySearchOutboxParams) error {
+	_, err := q.db.ExecContext(ctx, retrySearchOutbox,
+		arg.Status,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.ID,
+	)
+	return err
+}
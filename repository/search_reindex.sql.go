@@ -0,0 +1,141 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: search_reindex.sql
+
+package repository
+
+import (
+	"context"
+)
+
+const countProductsAfter = `-- name: CountProductsAfter :one
+SELECT count(*) FROM product WHERE id > $1
+`
+
+func (q *Queries) CountProductsAfter(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countProductsAfter, id)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createSearchReindex = `-- name: CreateSearchReindex :one
+INSERT INTO search_reindex DEFAULT VALUES RETURNING id, last_product_id, indexed, status, created_at, updated_at
+`
+
+func (q *Queries) CreateSearchReindex(ctx context.Context) (SearchReindex, error) {
+	row := q.db.QueryRowContext(ctx, createSearchReindex)
+	var i SearchReindex
+	err := row.Scan(
+		&i.ID,
+		&i.LastProductID,
+		&i.Indexed,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getProductNamesAfter = `-- name: GetProductNamesAfter :many
+SELECT id, name FROM product WHERE id > $1 ORDER BY id LIMIT $2
+`
+
+type GetProductNamesAfterParams struct {
+	ID    int64
+	Limit int32
+}
+
+type GetProductNamesAfterRow struct {
+	ID   int64
+	Name string
+}
+
+func (q *Queries) GetProductNamesAfter(ctx context.Context, arg GetProductNamesAfterParams) ([]GetProductNamesAfterRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProductNamesAfter, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProductNamesAfterRow
+	for rows.Next() {
+		var i GetProductNamesAfterRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSearchReindex = `-- name: GetSearchReindex :one
+SELECT id, last_product_id, indexed, status, created_at, updated_at FROM search_reindex WHERE id = $1
+`
+
+func (q *Queries) GetSearchReindex(ctx context.Context, id int64) (SearchReindex, error) {
+	row := q.db.QueryRowContext(ctx, getSearchReindex, id)
+	var i SearchReindex
+	err := row.Scan(
+		&i.ID,
+		&i.LastProductID,
+		&i.Indexed,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const tryLockSearchReindex = `-- name: TryLockSearchReindex :one
+SELECT pg_try_advisory_lock(hashtext('search_reindex'), $1::integer)::bool AS locked
+`
+
+func (q *Queries) TryLockSearchReindex(ctx context.Context, id int32) (bool, error) {
+	row := q.db.QueryRowContext(ctx, tryLockSearchReindex, id)
+	var locked bool
+	err := row.Scan(&locked)
+	return locked, err
+}
+
+const unlockSearchReindex = `-- name: UnlockSearchReindex :exec
+SELECT pg_advisory_unlock(hashtext('search_reindex'), $1::integer)
+`
+
+func (q *Queries) UnlockSearchReindex(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, unlockSearchReindex, id)
+	return err
+}
+
+const updateSearchReindex = `-- name: UpdateSearchReindex :exec
+UPDATE search_reindex
+SET
+    last_product_id = $2,
+    indexed = $3,
+    status = $4,
+    updated_at = now()
+WHERE id = $1
+`
+
+type UpdateSearchReindexParams struct {
+	ID            int64
+	LastProductID int64
+	Indexed       int64
+	Status        string
+}
+
+func (q *Queries) UpdateSearchReindex(ctx context.Context, arg UpdateSearchReindexParams) error {
+	_, err := q.db.ExecContext(ctx, updateSearchReindex,
+		arg.ID,
+		arg.LastProductID,
+		arg.Indexed,
+		arg.Status,
+	)
+	return err
+}
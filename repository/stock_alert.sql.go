@@ -0,0 +1,199 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: stock_alert.sql
+
+package repository
+
+import (
+	"context"
+)
+
+const createStockAlert = `-- name: CreateStockAlert :exec
+INSERT INTO
+    stock_alert (
+        product_id,
+        supplier_id,
+        alert_type,
+        inventory,
+        threshold
+    )
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateStockAlertParams struct {
+	ProductID  int64
+	SupplierID int64
+	AlertType  string
+	Inventory  int32
+	Threshold  int32
+}
+
+func (q *Queries) CreateStockAlert(ctx context.Context, arg CreateStockAlertParams) error {
+	_, err := q.db.ExecContext(ctx, createStockAlert,
+		arg.ProductID,
+		arg.SupplierID,
+		arg.AlertType,
+		arg.Inventory,
+		arg.Threshold,
+	)
+	return err
+}
+
+const getLowStockProducts = `-- name: GetLowStockProducts :many
+SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved
+FROM product
+WHERE
+    supplier_id = $1
+    AND reorder_threshold > 0
+    AND inventory <= reorder_threshold
+ORDER BY inventory, id
+`
+
+func (q *Queries) GetLowStockProducts(ctx context.Context, supplierID int64) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, getLowStockProducts, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.Thumbnail,
+			&i.Inventory,
+			&i.SupplierID,
+			&i.CategoryID,
+			&i.CreatedAt,
+			&i.Brand,
+			&i.ReorderThreshold,
+			&i.StockPolicy,
+			&i.BackorderLimit,
+			&i.PreorderUntil,
+			&i.AvailableAt,
+			&i.Reserved,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProductThreshold = `-- name: GetProductThreshold :one
+SELECT supplier_id, inventory, reorder_threshold FROM product WHERE id = $1
+`
+
+type GetProductThresholdRow struct {
+	SupplierID       int64
+	Inventory        int32
+	ReorderThreshold int32
+}
+
+func (q *Queries) GetProductThreshold(ctx context.Context, id int64) (GetProductThresholdRow, error) {
+	row := q.db.QueryRowContext(ctx, getProductThreshold, id)
+	var i GetProductThresholdRow
+	err := row.Scan(&i.SupplierID, &i.Inventory, &i.ReorderThreshold)
+	return i, err
+}
+
+const getStockAlertTxid = `-- name: GetStockAlertTxid :one
+SELECT txid FROM stock_alert WHERE id = $1 AND supplier_id = $2
+`
+
+type GetStockAlertTxidParams struct {
+	ID         int64
+	SupplierID int64
+}
+
+func (q *Queries) GetStockAlertTxid(ctx context.Context, arg GetStockAlertTxidParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getStockAlertTxid, arg.ID, arg.SupplierID)
+	var txid int64
+	err := row.Scan(&txid)
+	return txid, err
+}
+
+const getStockAlertsAfter = `-- name: GetStockAlertsAfter :many
+SELECT id, product_id, supplier_id, alert_type, inventory, threshold, created_at, txid
+FROM stock_alert
+WHERE
+    supplier_id = $1
+    AND (txid, id) > ($2::bigint, $3::bigint)
+    AND txid < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+ORDER BY txid, id
+LIMIT $4
+`
+
+type GetStockAlertsAfterParams struct {
+	SupplierID int64
+	AfterTxid  int64
+	AfterID    int64
+	RowLimit   int32
+}
+
+func (q *Queries) GetStockAlertsAfter(ctx context.Context, arg GetStockAlertsAfterParams) ([]StockAlert, error) {
+	rows, err := q.db.QueryContext(ctx, getStockAlertsAfter,
+		arg.SupplierID,
+		arg.AfterTxid,
+		arg.AfterID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StockAlert
+	for rows.Next() {
+		var i StockAlert
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.SupplierID,
+			&i.AlertType,
+			&i.Inventory,
+			&i.Threshold,
+			&i.CreatedAt,
+			&i.Txid,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setReorderThreshold = `-- name: SetReorderThreshold :execrows
+UPDATE product
+SET reorder_threshold = $3
+WHERE id = $1 AND supplier_id = $2
+`
+
+type SetReorderThresholdParams struct {
+	ID               int64
+	SupplierID       int64
+	ReorderThreshold int32
+}
+
+func (q *Queries) SetReorderThreshold(ctx context.Context, arg SetReorderThresholdParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setReorderThreshold, arg.ID, arg.SupplierID, arg.ReorderThreshold)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,244 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: stock_location.sql
+
+package repository
+
+import (
+	"context"
+)
+
+const addProductStock = `-- name: AddProductStock :execrows
+UPDATE product_stock
+SET
+    quantity = quantity + $1::integer
+WHERE
+    product_id = $2
+    AND location_id = $3
+    AND quantity + $1::integer >= 0
+`
+
+type AddProductStockParams struct {
+	Delta      int32
+	ProductID  int64
+	LocationID int64
+}
+
+func (q *Queries) AddProductStock(ctx context.Context, arg AddProductStockParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, addProductStock, arg.Delta, arg.ProductID, arg.LocationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createStockLocation = `-- name: CreateStockLocation :one
+INSERT INTO
+    stock_location (supplier_id, name, priority)
+VALUES ($1, $2, $3) RETURNING id, supplier_id, name, priority, created_at
+`
+
+type CreateStockLocationParams struct {
+	SupplierID int64
+	Name       string
+	Priority   int32
+}
+
+func (q *Queries) CreateStockLocation(ctx context.Context, arg CreateStockLocationParams) (StockLocation, error) {
+	row := q.db.QueryRowContext(ctx, createStockLocation, arg.SupplierID, arg.Name, arg.Priority)
+	var i StockLocation
+	err := row.Scan(
+		&i.ID,
+		&i.SupplierID,
+		&i.Name,
+		&i.Priority,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getProductStock = `-- name: GetProductStock :many
+SELECT
+    product_stock.location_id,
+    product_stock.quantity,
+    stock_location.name,
+    stock_location.priority
+FROM product_stock
+    JOIN stock_location ON stock_location.id = product_stock.location_id
+WHERE product_stock.product_id = $1
+ORDER BY stock_location.priority, product_stock.location_id
+`
+
+type GetProductStockRow struct {
+	LocationID int64
+	Quantity   int32
+	Name       string
+	Priority   int32
+}
+
+func (q *Queries) GetProductStock(ctx context.Context, productID int64) ([]GetProductStockRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProductStock, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProductStockRow
+	for rows.Next() {
+		var i GetProductStockRow
+		if err := rows.Scan(
+			&i.LocationID,
+			&i.Quantity,
+			&i.Name,
+			&i.Priority,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStockLocation = `-- name: GetStockLocation :one
+SELECT id, supplier_id, name, priority, created_at FROM stock_location WHERE id = $1
+`
+
+func (q *Queries) GetStockLocation(ctx context.Context, id int64) (StockLocation, error) {
+	row := q.db.QueryRowContext(ctx, getStockLocation, id)
+	var i StockLocation
+	err := row.Scan(
+		&i.ID,
+		&i.SupplierID,
+		&i.Name,
+		&i.Priority,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getStockLocationsBySupplier = `-- name: GetStockLocationsBySupplier :many
+SELECT id, supplier_id, name, priority, created_at
+FROM stock_location
+WHERE supplier_id = $1
+ORDER BY priority, id
+`
+
+func (q *Queries) GetStockLocationsBySupplier(ctx context.Context, supplierID int64) ([]StockLocation, error) {
+	rows, err := q.db.QueryContext(ctx, getStockLocationsBySupplier, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StockLocation
+	for rows.Next() {
+		var i StockLocation
+		if err := rows.Scan(
+			&i.ID,
+			&i.SupplierID,
+			&i.Name,
+			&i.Priority,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockProductStock = `-- name: LockProductStock :many
+SELECT
+    product_stock.location_id,
+    product_stock.quantity,
+    stock_location.priority
+FROM product_stock
+    JOIN stock_location ON stock_location.id = product_stock.location_id
+WHERE product_stock.product_id = $1
+ORDER BY product_stock.location_id
+FOR UPDATE OF product_stock
+`
+
+type LockProductStockRow struct {
+	LocationID int64
+	Quantity   int32
+	Priority   int32
+}
+
+func (q *Queries) LockProductStock(ctx context.Context, productID int64) ([]LockProductStockRow, error) {
+	rows, err := q.db.QueryContext(ctx, lockProductStock, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LockProductStockRow
+	for rows.Next() {
+		var i LockProductStockRow
+		if err := rows.Scan(&i.LocationID, &i.Quantity, &i.Priority); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const syncProductInventory = `-- name: SyncProductInventory :one
+UPDATE product
+SET inventory = (
+        SELECT
+            coalesce(sum(quantity), 0)
+        FROM product_stock
+        WHERE
+            product_stock.product_id = product.id
+    ) + least(inventory, 0)
+WHERE product.id = $1
+RETURNING inventory
+`
+
+func (q *Queries) SyncProductInventory(ctx context.Context, id int64) (int32, error) {
+	row := q.db.QueryRowContext(ctx, syncProductInventory, id)
+	var inventory int32
+	err := row.Scan(&inventory)
+	return inventory, err
+}
+
+const upsertProductStock = `-- name: UpsertProductStock :exec
+INSERT INTO
+    product_stock (
+        product_id,
+        location_id,
+        quantity
+    )
+VALUES ($1, $2, $3) ON CONFLICT (product_id, location_id) DO
+UPDATE
+SET
+    quantity = EXCLUDED.quantity
+`
+
+type UpsertProductStockParams struct {
+	ProductID  int64
+	LocationID int64
+	Quantity   int32
+}
+
+func (q *Queries) UpsertProductStock(ctx context.Context, arg UpsertProductStockParams) error {
+	_, err := q.db.ExecContext(ctx, upsertProductStock, arg.ProductID, arg.LocationID, arg.Quantity)
+	return err
+}
@@ -0,0 +1,109 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: stock_policy.sql
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+const getProductStockPolicy = `-- name: GetProductStockPolicy :one
+SELECT
+    inventory,
+    reserved,
+    stock_policy,
+    backorder_limit,
+    preorder_until
+FROM product
+WHERE id = $1
+`
+
+type GetProductStockPolicyRow struct {
+	Inventory      int32
+	Reserved       int32
+	StockPolicy    string
+	BackorderLimit int32
+	PreorderUntil  sql.NullTime
+}
+
+func (q *Queries) GetProductStockPolicy(ctx context.Context, id int64) (GetProductStockPolicyRow, error) {
+	row := q.db.QueryRowContext(ctx, getProductStockPolicy, id)
+	var i GetProductStockPolicyRow
+	err := row.Scan(
+		&i.Inventory,
+		&i.Reserved,
+		&i.StockPolicy,
+		&i.BackorderLimit,
+		&i.PreorderUntil,
+	)
+	return i, err
+}
+
+const lockProductStockPolicy = `-- name: LockProductStockPolicy :one
+SELECT
+    inventory,
+    reserved,
+    stock_policy,
+    backorder_limit,
+    preorder_until
+FROM product
+WHERE id = $1 FOR UPDATE
+`
+
+type LockProductStockPolicyRow struct {
+	Inventory      int32
+	Reserved       int32
+	StockPolicy    string
+	BackorderLimit int32
+	PreorderUntil  sql.NullTime
+}
+
+func (q *Queries) LockProductStockPolicy(ctx context.Context, id int64) (LockProductStockPolicyRow, error) {
+	row := q.db.QueryRowContext(ctx, lockProductStockPolicy, id)
+	var i LockProductStockPolicyRow
+	err := row.Scan(
+		&i.Inventory,
+		&i.Reserved,
+		&i.StockPolicy,
+		&i.BackorderLimit,
+		&i.PreorderUntil,
+	)
+	return i, err
+}
+
+const setStockPolicy = `-- name: SetStockPolicy :execrows
+UPDATE product
+SET
+    stock_policy = $3,
+    backorder_limit = $4,
+    preorder_until = $5,
+    available_at = $6
+WHERE id = $1 AND supplier_id = $2
+`
+
+type SetStockPolicyParams struct {
+	ID             int64
+	SupplierID     int64
+	StockPolicy    string
+	BackorderLimit int32
+	PreorderUntil  sql.NullTime
+	AvailableAt    sql.NullTime
+}
+
+func (q *Queries) SetStockPolicy(ctx context.Context, arg SetStockPolicyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setStockPolicy,
+		arg.ID,
+		arg.SupplierID,
+		arg.StockPolicy,
+		arg.BackorderLimit,
+		arg.PreorderUntil,
+		arg.AvailableAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
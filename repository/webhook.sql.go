@@ -0,0 +1,359 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.17.0
+// source: webhook.sql
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :many
+WITH claimed AS (
+    UPDATE webhook_delivery
+    SET next_attempt_at = $1
+    WHERE id IN (
+            SELECT id
+            FROM webhook_delivery
+            WHERE
+                status = 'pending'
+                AND next_attempt_at <= now()
+            ORDER BY id
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+    RETURNING id, subscription_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, delivered_at, created_at
+)
+SELECT
+    claimed.id, claimed.subscription_id, claimed.event_type, claimed.payload, claimed.status, claimed.attempts, claimed.response_status, claimed.last_error, claimed.next_attempt_at, claimed.delivered_at, claimed.created_at,
+    webhook_subscription.url,
+    webhook_subscription.secret
+FROM claimed
+    JOIN webhook_subscription ON webhook_subscription.id = claimed.subscription_id
+ORDER BY claimed.id
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	LeaseUntil time.Time
+	RowLimit   int32
+}
+
+type ClaimWebhookDeliveriesRow struct {
+	ID             int64
+	SubscriptionID int64
+	EventType      string
+	Payload        string
+	Status         string
+	Attempts       int32
+	ResponseStatus int32
+	LastError      string
+	NextAttemptAt  time.Time
+	DeliveredAt    sql.NullTime
+	CreatedAt      time.Time
+	Url            string
+	Secret         string
+}
+
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, claimWebhookDeliveries, arg.LeaseUntil, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimWebhookDeliveriesRow
+	for rows.Next() {
+		var i ClaimWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.ResponseStatus,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.DeliveredAt,
+			&i.CreatedAt,
+			&i.Url,
+			&i.Secret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createWebhookSubscription = `-- name: CreateWebhookSubscription :one
+INSERT INTO
+    webhook_subscription (
+        supplier_id,
+        url,
+        event_types,
+        secret
+    )
+VALUES ($1, $2, $3, $4) RETURNING id, supplier_id, url, event_types, secret, created_at
+`
+
+type CreateWebhookSubscriptionParams struct {
+	SupplierID int64
+	Url        string
+	EventTypes []string
+	Secret     string
+}
+
+func (q *Queries) CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error) {
+	row := q.db.QueryRowContext(ctx, createWebhookSubscription,
+		arg.SupplierID,
+		arg.Url,
+		pq.Array(arg.EventTypes),
+		arg.Secret,
+	)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.SupplierID,
+		&i.Url,
+		pq.Array(&i.EventTypes),
+		&i.Secret,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteWebhookSubscription = `-- name: DeleteWebhookSubscription :execrows
+DELETE FROM webhook_subscription WHERE id = $1 AND supplier_id = $2
+`
+
+type DeleteWebhookSubscriptionParams struct {
+	ID         int64
+	SupplierID int64
+}
+
+func (q *Queries) DeleteWebhookSubscription(ctx context.Context, arg DeleteWebhookSubscriptionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteWebhookSubscription, arg.ID, arg.SupplierID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const enqueueWebhookDeliveries = `-- name: EnqueueWebhookDeliveries :exec
+INSERT INTO
+    webhook_delivery (
+        subscription_id,
+        event_type,
+        payload
+    )
+SELECT
+    id,
+    $1::text,
+    $2::text
+FROM webhook_subscription
+WHERE
+    supplier_id = $3
+    AND $1::text = ANY(event_types)
+`
+
+type EnqueueWebhookDeliveriesParams struct {
+	EventType  string
+	Payload    string
+	SupplierID int64
+}
+
+func (q *Queries) EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) error {
+	_, err := q.db.ExecContext(ctx, enqueueWebhookDeliveries, arg.EventType, arg.Payload, arg.SupplierID)
+	return err
+}
+
+const getDeadWebhookDeliveries = `-- name: GetDeadWebhookDeliveries :many
+SELECT webhook_delivery.id, webhook_delivery.subscription_id, webhook_delivery.event_type, webhook_delivery.payload, webhook_delivery.status, webhook_delivery.attempts, webhook_delivery.response_status, webhook_delivery.last_error, webhook_delivery.next_attempt_at, webhook_delivery.delivered_at, webhook_delivery.created_at
+FROM webhook_delivery
+    JOIN webhook_subscription ON webhook_subscription.id = webhook_delivery.subscription_id
+WHERE
+    webhook_subscription.supplier_id = $1
+    AND ($2::bigint = 0 OR webhook_delivery.subscription_id = $2)
+    AND webhook_delivery.status = 'dead'
+ORDER BY webhook_delivery.id DESC
+LIMIT $4
+OFFSET $3
+`
+
+type GetDeadWebhookDeliveriesParams struct {
+	SupplierID     int64
+	SubscriptionID int64
+	RowOffset      int32
+	RowLimit       int32
+}
+
+func (q *Queries) GetDeadWebhookDeliveries(ctx context.Context, arg GetDeadWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, getDeadWebhookDeliveries,
+		arg.SupplierID,
+		arg.SubscriptionID,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.ResponseStatus,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.DeliveredAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhookSubscriptions = `-- name: GetWebhookSubscriptions :many
+SELECT id, supplier_id, url, event_types, secret, created_at
+FROM webhook_subscription
+WHERE supplier_id = $1
+ORDER BY id
+`
+
+func (q *Queries) GetWebhookSubscriptions(ctx context.Context, supplierID int64) ([]WebhookSubscription, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhookSubscriptions, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookSubscription
+	for rows.Next() {
+		var i WebhookSubscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.SupplierID,
+			&i.Url,
+			pq.Array(&i.EventTypes),
+			&i.Secret,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markWebhookDelivered = `-- name: MarkWebhookDelivered :exec
+UPDATE webhook_delivery
+SET
+    status = 'delivered',
+    attempts = attempts + 1,
+    response_status = $2,
+    last_error = '',
+    delivered_at = now()
+WHERE id = $1
+`
+
+type MarkWebhookDeliveredParams struct {
+	ID             int64
+	ResponseStatus int32
+}
+
+func (q *Queries) MarkWebhookDelivered(ctx context.Context, arg MarkWebhookDeliveredParams) error {
+	_, err := q.db.ExecContext(ctx, markWebhookDelivered, arg.ID, arg.ResponseStatus)
+	return err
+}
+
+const replayWebhookDeliveries = `-- name: ReplayWebhookDeliveries :execrows
+UPDATE webhook_delivery
+SET
+    status = 'pending',
+    attempts = 0,
+    next_attempt_at = now()
+FROM webhook_subscription
+WHERE
+    webhook_subscription.id = webhook_delivery.subscription_id
+    AND webhook_subscription.supplier_id = $1
+    AND ($2::bigint = 0 OR webhook_delivery.subscription_id = $2)
+    AND (
+        coalesce(cardinality($3::bigint[]), 0) = 0
+        OR webhook_delivery.id = ANY($3::bigint[])
+    )
+    AND webhook_delivery.status = 'dead'
+`
+
+type ReplayWebhookDeliveriesParams struct {
+	SupplierID     int64
+	SubscriptionID int64
+	Ids            []int64
+}
+
+func (q *Queries) ReplayWebhookDeliveries(ctx context.Context, arg ReplayWebhookDeliveriesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, replayWebhookDeliveries, arg.SupplierID, arg.SubscriptionID, pq.Array(arg.Ids))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const retryWebhookDelivery = `-- name: RetryWebhookDelivery :exec
+UPDATE webhook_delivery
+SET
+    status = $1,
+    attempts = attempts + 1,
+    response_status = $2,
+    last_error = $3,
+    next_attempt_at = $4
+WHERE id = $5
+`
+
+type RetryWebhookDeliveryParams struct {
+	Status         string
+	ResponseStatus int32
+	LastError      string
+	NextAttemptAt  time.Time
+	ID             int64
+}
+
+func (q *Queries) RetryWebhookDelivery(ctx context.Context, arg RetryWebhookDeliveryParams) error {
+	_, err := q.db.ExecContext(ctx, retryWebhookDelivery,
+		arg.Status,
+		arg.ResponseStatus,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.ID,
+	)
+	return err
+}
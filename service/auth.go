@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authPolicy is who may call a method
+type authPolicy int
+
+const (
+	// authNone is for public reads
+	authNone authPolicy = iota
+	// authOptional verifies the customer or service token when there is one
+	authOptional
+	authCustomer
+	authSupplier
+	authAdmin
+	// authService is for the other services of the cluster, they send a service token
+	authService
+)
+
+const productServicePrefix = "/ecommerce.ProductService/"
+
+// serviceTokenKey is the metadata carrying the token of an internal caller
+const serviceTokenKey = "x-service-token"
+
+// authPolicies lists every ProductService method, methods missing here are denied
+var authPolicies = map[string]authPolicy{
+	"Ping":                      authNone,
+	"GetProduct":                authNone,
+	"GetListProduct":            authNone,
+	"GetListProductByIDs":       authNone,
+	"GetListCategory":           authNone,
+	"GetCategoryBySupplier":     authNone,
+	"GetListProductInventory":   authNone,
+	"GetRelatedProducts":        authNone,
+	"GetSimilarProducts":        authNone,
+	"GetInventoryAvailability":  authNone,
+	"SearchProducts":            authNone,
+	"SuggestProducts":           authNone,
+	"SearchAndHydrate":          authNone,
+	"WatchProducts":             authNone,
+	"DescInventory":             authService,
+	"IncInventory":              authService,
+	"BatchDescInventory":        authService,
+	"ReserveInventory":          authService,
+	"CommitReservation":         authService,
+	"ReleaseReservation":        authService,
+	"TryDeductInventory":        authService,
+	"ConfirmDeduction":          authService,
+	"CancelDeduction":           authService,
+	"GetRecomendProduct":        authOptional,
+	"RecordCustomerActivity":    authOptional,
+	"BuyFlashSale":              authCustomer,
+	"CreateProduct":             authSupplier,
+	"UpdateProduct":             authSupplier,
+	"DeleteProduct":             authSupplier,
+	"GetProductBySupplier":      authSupplier,
+	"AddProductRelation":        authSupplier,
+	"RemoveProductRelation":     authSupplier,
+	"AdjustInventory":           authSupplier,
+	"CreateStockLocation":       authSupplier,
+	"GetStockLocations":         authSupplier,
+	"SetLocationStock":          authSupplier,
+	"SetReorderThreshold":       authSupplier,
+	"ListLowStockProducts":      authSupplier,
+	"WatchStockAlerts":          authSupplier,
+	"SetStockPolicy":            authSupplier,
+	"CreateFlashSale":           authSupplier,
+	"ImportStockCount":          authSupplier,
+	"CreateWebhook":             authSupplier,
+	"ListWebhooks":              authSupplier,
+	"DeleteWebhook":             authSupplier,
+	"ListDeadWebhookDeliveries": authSupplier,
+	"ReplayWebhookDeliveries":   authSupplier,
+	"DeleteProductByAdmin":      authAdmin,
+	"ApproveProduct":            authAdmin,
+	"CreateCategory":            authAdmin,
+	"GetInventoryHistory":       authAdmin,
+	"CheckInventoryConsistency": authAdmin,
+	"ReindexSearch":             authAdmin,
+}
+
+// Claims is the caller verified by auth-service, or the internal service verified by its token
+type Claims struct {
+	UserID int64
+	Role   pb.UserRole
+	// Service is the name of the calling service, empty for users
+	Service string
+}
+
+type serviceToken struct {
+	name string
+	hash [sha256.Size]byte
+}
+
+// SetServiceTokens sets the tokens of the services allowed to call the internal methods, as
+// comma separated name:token pairs. Without any, internal methods are denied.
+func (service *ProductService) SetServiceTokens(tokens string) error {
+	var serviceTokens []serviceToken
+	for _, pair := range strings.Split(tokens, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, token, ok := strings.Cut(pair, ":")
+		if !ok || name == "" || token == "" {
+			return fmt.Errorf("service token of %q is not name:token", name)
+		}
+		serviceTokens = append(serviceTokens, serviceToken{name: name, hash: sha256.Sum256([]byte(token))})
+	}
+	service.serviceTokens = serviceTokens
+	return nil
+}
+
+// serviceName returns the service owning token, every token is compared so the time taken
+// tells nothing about them
+func (service *ProductService) serviceName(token string) (string, bool) {
+	hash := sha256.Sum256([]byte(token))
+	var name string
+	for _, serviceToken := range service.serviceTokens {
+		if subtle.ConstantTimeCompare(hash[:], serviceToken.hash[:]) == 1 {
+			name = serviceToken.name
+		}
+	}
+	return name, name != ""
+}
+
+type claimsKey struct{}
+
+// ClaimsFromContext returns the claims of the caller, there are none for methods
+// without authorization
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(Claims)
+	return claims, ok
+}
+
+// claimedSupplierID is the id of the calling supplier, the interceptor already
+// rejected callers who are not suppliers
+func claimedSupplierID(ctx context.Context) int64 {
+	claims, _ := ClaimsFromContext(ctx)
+	return claims.UserID
+}
+
+// authorize checks the caller against the policy of the method and returns the context with its claims
+func (service *ProductService) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	policy, ok := authPolicies[strings.TrimPrefix(fullMethod, productServicePrefix)]
+	if !ok || !strings.HasPrefix(fullMethod, productServicePrefix) {
+		return nil, status.Error(codes.PermissionDenied, "method has no authorization policy")
+	}
+	if policy == authNone {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	hasToken := len(md.Get("authorization")) > 0
+	hasServiceToken := len(md.Get(serviceTokenKey)) > 0
+	switch {
+	case policy == authService, policy == authOptional && !hasToken && hasServiceToken:
+		return service.authorizeService(ctx, md.Get(serviceTokenKey))
+	case !hasToken && policy == authOptional:
+		return ctx, nil
+	case !hasToken:
+		return nil, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+
+	// the caller's token goes on to auth-service
+	authCtx := metadata.NewOutgoingContext(ctx, md)
+	var claims *pb.UserClaimsResponse
+	var err error
+	switch policy {
+	case authSupplier:
+		claims, err = service.authClient.SupplierAuthorization(authCtx, &empty.Empty{})
+	case authAdmin:
+		claims, err = service.authClient.AdminAuthorization(authCtx, &empty.Empty{})
+	default:
+		claims, err = service.authClient.CustomerAuthorization(authCtx, &empty.Empty{})
+	}
+	if err != nil {
+		if code := status.Code(err); code == codes.Unauthenticated || code == codes.PermissionDenied {
+			return nil, err
+		}
+		return nil, status.Error(codes.Unavailable, "auth service: "+err.Error())
+	}
+	userID, err := strconv.ParseInt(claims.GetId(), 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user id in claims")
+	}
+
+	return context.WithValue(ctx, claimsKey{}, Claims{
+		UserID: userID,
+		Role:   claims.GetUserRole(),
+	}), nil
+}
+
+// authorizeService checks the token of an internal caller
+func (service *ProductService) authorizeService(ctx context.Context, tokens []string) (context.Context, error) {
+	if len(tokens) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "service token is required")
+	}
+	name, ok := service.serviceName(tokens[0])
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "unknown service token")
+	}
+	return context.WithValue(ctx, claimsKey{}, Claims{Service: name}), nil
+}
+
+// UnaryAuthInterceptor authorizes unary calls with the policy of their method
+func (service *ProductService) UnaryAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := service.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream authorizedStream) Context() context.Context {
+	return stream.ctx
+}
+
+// StreamAuthInterceptor authorizes streaming calls with the policy of their method
+func (service *ProductService) StreamAuthInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := service.authorize(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, authorizedStream{ServerStream: stream, ctx: ctx})
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const flashSaleSweepBatch = 100
+
+func flashSaleReference(flashSaleID int64) string {
+	return "flash_sale:" + strconv.FormatInt(flashSaleID, 10)
+}
+
+func flashSaleResponse(sale repository.FlashSale) *pb.FlashSale {
+	return &pb.FlashSale{
+		FlashSaleId:      sale.ID,
+		ProductId:        sale.ProductID,
+		SupplierId:       sale.SupplierID,
+		SalePrice:        sale.SalePrice,
+		StartsAt:         timestamppb.New(sale.StartsAt),
+		EndsAt:           timestamppb.New(sale.EndsAt),
+		Allocated:        sale.Allocated,
+		Remaining:        sale.Remaining,
+		PerCustomerLimit: sale.PerCustomerLimit,
+		LocationId:       sale.LocationID,
+	}
+}
+
+// CreateFlashSale schedules a sale of allocated items of a product with its own counter. The items
+// leave the regular inventory when the sale starts, what is left when it ends goes back to it.
+func (service *ProductService) CreateFlashSale(ctx context.Context, req *pb.CreateFlashSaleRequest) (*pb.FlashSale, error) {
+	supplierID := claimedSupplierID(ctx)
+	if req.GetAllocated() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "allocated quantity must be positive")
+	}
+	if req.GetPerCustomerLimit() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "per customer limit must be positive")
+	}
+	if req.GetStartsAt() == nil || req.GetEndsAt() == nil {
+		return nil, status.Error(codes.InvalidArgument, "sale window is required")
+	}
+	startsAt, endsAt := req.GetStartsAt().AsTime(), req.GetEndsAt().AsTime()
+	if !endsAt.After(startsAt) || !endsAt.After(time.Now()) {
+		return nil, status.Error(codes.InvalidArgument, "sale must end after it starts and in the future")
+	}
+	product, err := service.productStore.GetProductByID(ctx, req.GetProductId())
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && product.SupplierID != supplierID) {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if req.GetSalePrice() <= 0 || req.GetSalePrice() >= product.Price {
+		return nil, status.Error(codes.InvalidArgument, "sale price must be below the product price")
+	}
+
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	inventory, err := queries.LockProductInventory(ctx, req.GetProductId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	overlapping, err := queries.CountOverlappingFlashSales(ctx, repository.CountOverlappingFlashSalesParams{
+		ProductID: req.GetProductId(),
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if overlapping > 0 {
+		return nil, status.Error(codes.FailedPrecondition, "product already has a flash sale in this window")
+	}
+	// the sale only takes stock on hand, never backorders; a later sale takes what is on hand
+	// when it starts
+	reserved, err := queries.GetReservedInventory(ctx, req.GetProductId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !startsAt.After(time.Now()) && inventory-reserved < req.GetAllocated() {
+		return nil, insufficientInventory(req.GetProductId(), req.GetAllocated(), inventory-reserved)
+	}
+
+	sale, err := queries.CreateFlashSale(ctx, repository.CreateFlashSaleParams{
+		ProductID:        req.GetProductId(),
+		SupplierID:       supplierID,
+		SalePrice:        req.GetSalePrice(),
+		StartsAt:         startsAt,
+		EndsAt:           endsAt,
+		Allocated:        req.GetAllocated(),
+		PerCustomerLimit: req.GetPerCustomerLimit(),
+		LocationID:       req.GetLocationId(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !startsAt.After(time.Now()) {
+		sale, err = service.startFlashSale(ctx, queries, sale)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return flashSaleResponse(sale), nil
+}
+
+// BuyFlashSale takes count items from a running sale for a customer, within the per customer limit
+func (service *ProductService) BuyFlashSale(ctx context.Context, req *pb.BuyFlashSaleRequest) (*pb.BuyFlashSaleResponse, error) {
+	if req.GetCount() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "count must be positive")
+	}
+	customerID := customerID(ctx, req.GetCustomerId())
+	if customerID == 0 {
+		return nil, status.Error(codes.InvalidArgument, "customer id is required")
+	}
+
+	// the key belongs to the customer the purchase is for, not to the id the caller sent
+	keyed := proto.Clone(req).(*pb.BuyFlashSaleRequest)
+	keyed.CustomerId = customerID
+
+	result := &pb.BuyFlashSaleResponse{}
+	err := service.runIdempotent(ctx, req.GetIdempotencyKey(), "BuyFlashSale", keyed, result, func(queries *repository.Queries) error {
+		sale, err := queries.GetFlashSale(ctx, req.GetFlashSaleId())
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Error(codes.NotFound, "flash sale not found")
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		now := time.Now()
+		if now.Before(sale.StartsAt) || !now.Before(sale.EndsAt) {
+			return status.Error(codes.FailedPrecondition, "flash sale is not running")
+		}
+		if !sale.Held {
+			// the sweeper starts sales once a minute, the first buyer doesn't wait for it
+			sale, err = service.startFlashSale(ctx, queries, sale)
+			if err != nil {
+				return err
+			}
+		}
+		if req.GetCount() > sale.PerCustomerLimit {
+			return status.Errorf(codes.FailedPrecondition, "at most %d items per customer", sale.PerCustomerLimit)
+		}
+
+		remaining, err := queries.DescFlashSale(ctx, repository.DescFlashSaleParams{
+			Count: req.GetCount(),
+			ID:    sale.ID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Error(codes.FailedPrecondition, "flash sale is sold out")
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		// the purchase is recorded once the sale had the items, a sold out sale leaves the
+		// customer's quota untouched
+		added, err := queries.AddFlashSalePurchase(ctx, repository.AddFlashSalePurchaseParams{
+			FlashSaleID:      sale.ID,
+			CustomerID:       customerID,
+			Quantity:         req.GetCount(),
+			PerCustomerLimit: sale.PerCustomerLimit,
+		})
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if added == 0 {
+			// the error rolls back the items taken from the sale
+			return status.Errorf(codes.FailedPrecondition, "at most %d items per customer", sale.PerCustomerLimit)
+		}
+
+		result.Message = "OK"
+		result.SalePrice = sale.SalePrice
+		result.Remaining = remaining
+		if sale.LocationID != 0 {
+			result.ListAllocation = []*pb.LocationAllocation{
+				{ProductId: sale.ProductID, LocationId: sale.LocationID, Count: req.GetCount()},
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// applyFlashSale shows the sale price of a product while its flash sale runs
+func (service *ProductService) applyFlashSale(ctx context.Context, product *pb.Product) {
+	sale, err := service.productStore.GetCurrentFlashSale(ctx, product.ProductId)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Println("get flash sale error: ", err)
+		}
+		return
+	}
+	product.OriginalPrice = product.Price
+	product.Price = sale.SalePrice
+	product.FlashSale = flashSaleResponse(sale)
+}
+
+// startFlashSale takes the items of a sale whose window opened out of the regular inventory, as
+// many as are on hand when fewer than allocated. A sale started meanwhile is returned as it is.
+func (service *ProductService) startFlashSale(ctx context.Context, queries *repository.Queries, sale repository.FlashSale) (repository.FlashSale, error) {
+	inventory, err := queries.LockProductInventory(ctx, sale.ProductID)
+	if err != nil {
+		return sale, status.Error(codes.Internal, err.Error())
+	}
+	reserved, err := queries.GetReservedInventory(ctx, sale.ProductID)
+	if err != nil {
+		return sale, status.Error(codes.Internal, err.Error())
+	}
+	held := sale.Allocated
+	if inventory-reserved < held {
+		held = inventory - reserved
+	}
+	if held < 0 {
+		held = 0
+	}
+
+	started, err := queries.StartFlashSale(ctx, repository.StartFlashSaleParams{
+		Remaining: held,
+		ID:        sale.ID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// started by another instance, or its window closed
+		current, err := queries.GetFlashSale(ctx, sale.ID)
+		if err != nil {
+			return sale, status.Error(codes.Internal, err.Error())
+		}
+		return current, nil
+	}
+	if err != nil {
+		return sale, status.Error(codes.Internal, err.Error())
+	}
+	if held > 0 {
+		err = service.adjustInventory(ctx, queries, started.ProductID, started.LocationID, -held, reasonFlashSaleHold, supplierActor(started.SupplierID), flashSaleReference(started.ID), "")
+		if err != nil {
+			return sale, err
+		}
+	}
+	return started, nil
+}
+
+// startDueFlashSale starts a sale the sweeper found due in a transaction of its own
+func (service *ProductService) startDueFlashSale(ctx context.Context, flashSaleID int64) error {
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	sale, err := queries.GetFlashSale(ctx, flashSaleID)
+	if err != nil {
+		return err
+	}
+	if _, err := service.startFlashSale(ctx, queries, sale); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// endFlashSale returns the unsold items of an ended sale to the regular inventory
+func (service *ProductService) endFlashSale(ctx context.Context, flashSaleID int64) error {
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	sale, err := queries.EndFlashSale(ctx, flashSaleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// ended by another instance
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// a sale that never started took nothing from the inventory
+	if sale.Held && sale.Remaining > 0 {
+		err = service.adjustInventory(ctx, queries, sale.ProductID, sale.LocationID, sale.Remaining, reasonFlashSaleRelease, supplierActor(sale.SupplierID), flashSaleReference(sale.ID), "")
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SweepFlashSales starts the flash sales whose window opened and ends those whose window has passed
+// every interval until ctx is done
+func (service *ProductService) SweepFlashSales(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			starting, err := service.productStore.GetStartingFlashSales(ctx, flashSaleSweepBatch)
+			if err != nil {
+				log.Println("sweep flash sales error: ", err)
+				continue
+			}
+			for _, id := range starting {
+				if err := service.startDueFlashSale(ctx, id); err != nil {
+					log.Printf("start flash sale %d error: %v", id, err)
+				}
+			}
+
+			ended, err := service.productStore.GetEndedFlashSales(ctx, flashSaleSweepBatch)
+			if err != nil {
+				log.Println("sweep flash sales error: ", err)
+				continue
+			}
+			for _, id := range ended {
+				if err := service.endFlashSale(ctx, id); err != nil {
+					log.Printf("end flash sale %d error: %v", id, err)
+				}
+			}
+		}
+	}
+}
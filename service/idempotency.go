@@ -0,0 +1,170 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/e-commerce-microservices/product-service/repository"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultIdempotencyRetention is how long processed keys are kept when not configured
+const DefaultIdempotencyRetention = 24 * time.Hour
+
+// recordedCodes are the errors stored with an idempotency key, what the failed call wrote is
+// rolled back and the caller gets the same answer on replay. Other errors roll the key back to allow a retry.
+var recordedCodes = map[codes.Code]bool{
+	codes.InvalidArgument:    true,
+	codes.NotFound:           true,
+	codes.FailedPrecondition: true,
+}
+
+// requestHash identifies the request a key was claimed for, so a key reused for another
+// request is refused instead of replaying a result that doesn't belong to it
+func requestHash(method string, req proto.Message) ([]byte, error) {
+	payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write(payload)
+	return hash.Sum(nil), nil
+}
+
+// runIdempotent runs op in a transaction at most once per key. op fills result, which is
+// stored with the key; a replay of the same req with the key returns the stored result or error instead.
+func (service *ProductService) runIdempotent(ctx context.Context, key, method string, req, result proto.Message, op func(queries *repository.Queries) error) error {
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	if key == "" {
+		if err := op(queries); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		return nil
+	}
+
+	hash, err := requestHash(method, req)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	// a concurrent request with the same key waits here until the first one commits
+	claimed, err := queries.ClaimIdempotencyKey(ctx, repository.ClaimIdempotencyKeyParams{
+		Key:         key,
+		Method:      method,
+		RequestHash: hash,
+	})
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if claimed == 0 {
+		return replayIdempotent(ctx, queries, key, method, hash, result)
+	}
+
+	// a recorded error is saved without what op wrote before failing
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT idempotent_op"); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	var response, errorStatus []byte
+	if opErr := op(queries); opErr != nil {
+		st := status.Convert(opErr)
+		if !recordedCodes[st.Code()] {
+			return opErr
+		}
+		if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT idempotent_op"); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		errorStatus, err = proto.Marshal(st.Proto())
+	} else {
+		response, err = proto.Marshal(result)
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	err = queries.SaveIdempotencyResult(ctx, repository.SaveIdempotencyResultParams{
+		Key:         key,
+		Response:    response,
+		ErrorStatus: errorStatus,
+	})
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if err := tx.Commit(); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if errorStatus != nil {
+		return replayStatus(errorStatus)
+	}
+	return nil
+}
+
+func replayIdempotent(ctx context.Context, queries *repository.Queries, key, method string, hash []byte, result proto.Message) error {
+	processed, err := queries.GetIdempotencyKey(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Error(codes.Aborted, "idempotency key is being processed, retry later")
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if processed.Method != method {
+		return status.Errorf(codes.InvalidArgument, "idempotency key was used for %s", processed.Method)
+	}
+	if processed.RequestHash != nil && !bytes.Equal(processed.RequestHash, hash) {
+		return status.Error(codes.InvalidArgument, "idempotency key was used for a different request")
+	}
+	if processed.ErrorStatus != nil {
+		return replayStatus(processed.ErrorStatus)
+	}
+	if err := proto.Unmarshal(processed.Response, result); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+func replayStatus(data []byte) error {
+	st := &spb.Status{}
+	if err := proto.Unmarshal(data, st); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return status.FromProto(st).Err()
+}
+
+// SweepIdempotencyKeys deletes keys older than retention every interval until ctx is done
+func (service *ProductService) SweepIdempotencyKeys(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := service.productStore.DeleteIdempotencyKeysBefore(ctx, time.Now().Add(-retention))
+			if err != nil {
+				log.Println("sweep idempotency keys error: ", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("deleted %d idempotency keys", deleted)
+			}
+		}
+	}
+}
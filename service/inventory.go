@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// insufficientInventory is a FailedPrecondition error carrying a pb.InventoryShortage detail,
+// callers read it back with status.Convert(err).Details()
+func insufficientInventory(productID int64, requested, available int32) error {
+	st := status.Newf(codes.FailedPrecondition, "only %d items of product %d available", available, productID)
+	detailed, err := st.WithDetails(&pb.InventoryShortage{
+		ProductId: productID,
+		Requested: requested,
+		Available: available,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// shortageError explains why a decrement of productID updated no row
+func shortageError(ctx context.Context, queries *repository.Queries, productID int64, requested int32) error {
+	product, err := queries.GetProductStockPolicy(ctx, productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Error(codes.NotFound, "product not found")
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	available := sellable(product.Inventory-product.Reserved, product.StockPolicy, product.BackorderLimit, product.PreorderUntil)
+	return insufficientInventory(productID, requested, available)
+}
+
+// mergeInventoryItems sums the count of repeated products and sorts them by product id,
+// so that concurrent batches lock rows in the same order
+func mergeInventoryItems(items []*pb.InventoryItem) ([]*pb.InventoryItem, error) {
+	countByProduct := make(map[int64]int32, len(items))
+	for _, item := range items {
+		if item.GetCount() <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "count of product %d must be positive", item.GetProductId())
+		}
+		countByProduct[item.GetProductId()] += item.GetCount()
+	}
+
+	merged := make([]*pb.InventoryItem, 0, len(countByProduct))
+	for productID, count := range countByProduct {
+		merged = append(merged, &pb.InventoryItem{
+			ProductId: productID,
+			Count:     count,
+		})
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].ProductId < merged[j].ProductId
+	})
+	return merged, nil
+}
+
+// lockAvailableInventory locks the products in id order and returns on-hand minus active reservations,
+// plus what their stock policy allows to sell beyond it. Products that don't exist are missing from the result.
+func lockAvailableInventory(ctx context.Context, queries *repository.Queries, items []*pb.InventoryItem) (map[int64]int32, error) {
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductId)
+	}
+
+	rows, err := queries.LockProductsInventory(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make(map[int64]int32, len(rows))
+	for _, row := range rows {
+		available[row.ID] = sellable(row.Inventory-row.Reserved, row.StockPolicy, row.BackorderLimit, row.PreorderUntil)
+	}
+	return available, nil
+}
+
+// maxAvailabilityProducts bounds the products of one GetInventoryAvailability request
+const maxAvailabilityProducts = 200
+
+// GetInventoryAvailability returns the stock of many products at once, e.g. to validate a cart.
+// Available is what can be ordered right now: on-hand minus reservations plus backorder allowance.
+func (service *ProductService) GetInventoryAvailability(ctx context.Context, req *pb.GetInventoryAvailabilityRequest) (*pb.GetInventoryAvailabilityResponse, error) {
+	if len(req.GetProductIds()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "product ids is empty")
+	}
+	if len(req.GetProductIds()) > maxAvailabilityProducts {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d products per request", maxAvailabilityProducts)
+	}
+
+	rows, err := service.productStore.GetInventoryAvailability(ctx, req.GetProductIds())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	result := &pb.GetInventoryAvailabilityResponse{
+		ListAvailability: make([]*pb.InventoryAvailability, 0, len(rows)),
+	}
+	found := make(map[int64]bool, len(rows))
+	for _, row := range rows {
+		unreserved := row.Inventory - row.Reserved
+		available := sellable(unreserved, row.StockPolicy, row.BackorderLimit, row.PreorderUntil)
+		if available < 0 {
+			available = 0
+		}
+		result.ListAvailability = append(result.ListAvailability, &pb.InventoryAvailability{
+			ProductId:    row.ID,
+			OnHand:       row.Inventory,
+			Reserved:     row.Reserved,
+			Available:    available,
+			Availability: availabilityStatus(unreserved, available, row.StockPolicy),
+		})
+		found[row.ID] = true
+	}
+	for _, id := range req.GetProductIds() {
+		if !found[id] {
+			result.NotFoundIds = append(result.NotFoundIds, id)
+			found[id] = true
+		}
+	}
+
+	return result, nil
+}
+
+// BatchDescInventory decrements every item of an order in one transaction, or none of them
+func (service *ProductService) BatchDescInventory(ctx context.Context, req *pb.BatchDescInventoryRequest) (*pb.BatchDescInventoryResponse, error) {
+	items, err := mergeInventoryItems(req.GetListItem())
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "list item is empty")
+	}
+
+	result := &pb.BatchDescInventoryResponse{}
+	err = service.runIdempotent(ctx, req.GetIdempotencyKey(), "BatchDescInventory", req, result, func(queries *repository.Queries) error {
+		available, err := lockAvailableInventory(ctx, queries, items)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		for _, item := range items {
+			count, ok := available[item.ProductId]
+			if !ok {
+				return status.Errorf(codes.NotFound, "product %d not found", item.ProductId)
+			}
+			if count < item.Count {
+				result.ListShortage = append(result.ListShortage, &pb.InventoryShortage{
+					ProductId: item.ProductId,
+					Requested: item.Count,
+					Available: count,
+				})
+			}
+		}
+		if len(result.ListShortage) > 0 {
+			result.Message = "Sản phẩm không đủ số lượng"
+			return nil
+		}
+
+		for _, item := range items {
+			balance, err := queries.DescInventory(ctx, repository.DescInventoryParams{
+				Inventory: item.Count,
+				ID:        item.ProductId,
+			})
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+			err = recordInventoryChange(ctx, queries, inventoryChange{
+				productID: item.ProductId,
+				delta:     -item.Count,
+				balance:   balance,
+				reason:    reasonOrderDecrement,
+				actor:     actorOrderService,
+				reference: orderReference(req.GetOrderId()),
+			})
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+			allocations, err := service.allocateLocations(ctx, queries, item.ProductId, item.Count, balance)
+			if err != nil {
+				return err
+			}
+			result.ListAllocation = append(result.ListAllocation, allocations...)
+		}
+		result.Message = "OK"
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
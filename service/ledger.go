@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// inventory ledger reasons
+const (
+	reasonCreate            = "create"
+	reasonSupplierUpdate    = "supplier_update"
+	reasonOrderDecrement    = "order_decrement"
+	reasonOrderCancel       = "order_cancel"
+	reasonReservationCommit = "reservation_commit"
+	reasonAdjustment        = "adjustment"
+	reasonFlashSaleHold     = "flash_sale_hold"
+	reasonFlashSaleRelease  = "flash_sale_release"
+	reasonStockCount        = "stock_count"
+)
+
+const (
+	actorOrderService = "order-service"
+
+	defaultHistoryLimit = 50
+	maxNoteLength       = 500
+)
+
+func supplierActor(supplierID int64) string {
+	return "supplier:" + strconv.FormatInt(supplierID, 10)
+}
+
+// checkNote bounds the free text a supplier attaches to a stock change
+func checkNote(note string) error {
+	if utf8.RuneCountInString(note) > maxNoteLength {
+		return status.Errorf(codes.InvalidArgument, "note must be at most %d characters", maxNoteLength)
+	}
+	return nil
+}
+
+func orderReference(orderID int64) string {
+	if orderID == 0 {
+		return ""
+	}
+	return "order:" + strconv.FormatInt(orderID, 10)
+}
+
+// inventoryChange is one movement of a product's stock
+type inventoryChange struct {
+	productID int64
+	delta     int32
+	balance   int32
+	reason    string
+	actor     string
+	reference string
+	note      string
+}
+
+// recordInventoryChange appends the change to the ledger, raises a stock alert when the
+// reorder threshold is crossed and an event when the stock runs out or comes back. It must
+// run in the transaction that changed the inventory.
+func recordInventoryChange(ctx context.Context, queries *repository.Queries, change inventoryChange) error {
+	if change.delta == 0 {
+		return nil
+	}
+	err := queries.InsertInventoryLedger(ctx, repository.InsertInventoryLedgerParams{
+		ProductID: change.productID,
+		Delta:     change.delta,
+		Balance:   change.balance,
+		Reason:    change.reason,
+		Actor:     change.actor,
+		Reference: change.reference,
+		Note:      change.note,
+	})
+	if err != nil {
+		return err
+	}
+	if err := detectStockAlert(ctx, queries, change.productID, change.balance-change.delta, change.balance); err != nil {
+		return err
+	}
+	if change.reason == reasonCreate {
+		// the created event already carries the stock
+		return nil
+	}
+	eventType, changed := stockEventType(change.balance-change.delta, change.balance)
+	if !changed {
+		return nil
+	}
+	return enqueueProductEvent(ctx, queries, eventType, change.productID)
+}
+
+// GetInventoryHistory returns the stock movements of a product, newest first
+func (service *ProductService) GetInventoryHistory(ctx context.Context, req *pb.GetInventoryHistoryRequest) (*pb.GetInventoryHistoryResponse, error) {
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	entries, err := service.productStore.GetInventoryHistory(ctx, repository.GetInventoryHistoryParams{
+		ProductID: req.GetProductId(),
+		Limit:     limit,
+		Offset:    req.GetOffset(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	result := make([]*pb.InventoryLedgerEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, &pb.InventoryLedgerEntry{
+			Id:        entry.ID,
+			ProductId: entry.ProductID,
+			Delta:     entry.Delta,
+			Balance:   entry.Balance,
+			Reason:    entry.Reason,
+			Actor:     entry.Actor,
+			Reference: entry.Reference,
+			Note:      entry.Note,
+			CreatedAt: timestamppb.New(entry.CreatedAt),
+		})
+	}
+
+	return &pb.GetInventoryHistoryResponse{
+		ListEntry: result,
+	}, nil
+}
+
+// AdjustInventory lets a supplier correct the stock of a product by hand
+func (service *ProductService) AdjustInventory(ctx context.Context, req *pb.AdjustInventoryRequest) (*pb.GeneralResponse, error) {
+	supplierID := claimedSupplierID(ctx)
+	if req.GetDelta() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "delta must not be zero")
+	}
+	if err := checkNote(req.GetNote()); err != nil {
+		return nil, err
+	}
+	if err := service.checkSupplierProducts(ctx, supplierID, req.GetProductId()); err != nil {
+		return nil, err
+	}
+
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	err = service.adjustInventory(ctx, queries, req.GetProductId(), req.GetLocationId(), req.GetDelta(), reasonAdjustment, supplierActor(supplierID), "", req.GetNote())
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.GeneralResponse{
+		Message: "OK",
+	}, nil
+}
+
+// adjustInventory adds delta to the stock of a product and records it, stock can't go below zero.
+// Products managed per location must name the location that is adjusted.
+func (service *ProductService) adjustInventory(ctx context.Context, queries *repository.Queries, productID, locationID int64, delta int32, reason, actor, reference, note string) error {
+	balance, err := queries.AdjustInventory(ctx, repository.AdjustInventoryParams{
+		Delta: delta,
+		ID:    productID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		inventory, err := queries.GetProductInventory(ctx, productID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Error(codes.NotFound, "product not found")
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		return status.Errorf(codes.FailedPrecondition, "inventory of product %d can't go below zero, current %d", productID, inventory)
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if locationID == 0 {
+		managed, err := hasLocationStock(ctx, queries, productID)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if managed {
+			return status.Error(codes.InvalidArgument, "location id is required, inventory is managed per stock location")
+		}
+	} else if err := service.restockLocation(ctx, queries, productID, locationID, delta); err != nil {
+		return err
+	}
+
+	err = recordInventoryChange(ctx, queries, inventoryChange{
+		productID: productID,
+		delta:     delta,
+		balance:   balance,
+		reason:    reason,
+		actor:     actor,
+		reference: reference,
+		note:      note,
+	})
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// CheckInventoryConsistency replays the ledger against the current inventory. With a product id
+// the whole history is replayed to find the first entry whose balance doesn't follow from the
+// previous ones; without, every product whose ledger sum differs from its inventory is returned.
+func (service *ProductService) CheckInventoryConsistency(ctx context.Context, req *pb.CheckInventoryConsistencyRequest) (*pb.CheckInventoryConsistencyResponse, error) {
+	if req.GetProductId() == 0 {
+		mismatches, err := service.productStore.GetInventoryMismatches(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		result := make([]*pb.CheckInventoryConsistencyResponse_InventoryMismatch, 0, len(mismatches))
+		for _, mismatch := range mismatches {
+			result = append(result, &pb.CheckInventoryConsistencyResponse_InventoryMismatch{
+				ProductId:     mismatch.ID,
+				Inventory:     mismatch.Inventory,
+				LedgerBalance: mismatch.LedgerBalance,
+			})
+		}
+		return &pb.CheckInventoryConsistencyResponse{
+			ListMismatch: result,
+		}, nil
+	}
+
+	inventory, err := service.productStore.GetProductInventory(ctx, req.GetProductId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	entries, err := service.productStore.GetInventoryLedger(ctx, req.GetProductId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	var balance int32
+	var divergentEntryID int64
+	for _, entry := range entries {
+		balance += entry.Delta
+		if divergentEntryID == 0 && balance != entry.Balance {
+			divergentEntryID = entry.ID
+		}
+	}
+	if balance == inventory && divergentEntryID == 0 {
+		return &pb.CheckInventoryConsistencyResponse{}, nil
+	}
+
+	return &pb.CheckInventoryConsistencyResponse{
+		ListMismatch: []*pb.CheckInventoryConsistencyResponse_InventoryMismatch{
+			{
+				ProductId:        req.GetProductId(),
+				Inventory:        inventory,
+				LedgerBalance:    balance,
+				DivergentEntryId: divergentEntryID,
+			},
+		},
+	}, nil
+}
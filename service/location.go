@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// allocation strategies choosing the stock location an order is shipped from
+const (
+	// AllocationPriority ships from the location with the lowest priority value first
+	AllocationPriority = "priority"
+	// AllocationMostStock ships from the location holding the most stock first
+	AllocationMostStock = "most_stock"
+)
+
+// SetAllocationStrategy changes how DescInventory picks stock locations
+func (service *ProductService) SetAllocationStrategy(strategy string) error {
+	switch strategy {
+	case AllocationPriority, AllocationMostStock:
+		service.allocationStrategy = strategy
+		return nil
+	}
+	return fmt.Errorf("unknown allocation strategy %q", strategy)
+}
+
+func locationReference(locationID int64) string {
+	return "location:" + strconv.FormatInt(locationID, 10)
+}
+
+// sortLocations orders locations by the allocation strategy
+func (service *ProductService) sortLocations(locations []repository.LockProductStockRow) {
+	sort.SliceStable(locations, func(i, j int) bool {
+		if service.allocationStrategy == AllocationMostStock && locations[i].Quantity != locations[j].Quantity {
+			return locations[i].Quantity > locations[j].Quantity
+		}
+		if locations[i].Priority != locations[j].Priority {
+			return locations[i].Priority < locations[j].Priority
+		}
+		return locations[i].LocationID < locations[j].LocationID
+	})
+}
+
+// allocateLocations takes count items of a product from its stock locations after the
+// product inventory was decremented to balance. A single location is used whenever one holds enough,
+// otherwise the order is split in strategy order. Items sold beyond the stock on hand are backordered
+// and allocated nowhere, neither are products without locations.
+func (service *ProductService) allocateLocations(ctx context.Context, queries *repository.Queries, productID int64, count, balance int32) ([]*pb.LocationAllocation, error) {
+	count = fromStock(count, balance)
+	if count == 0 {
+		return nil, nil
+	}
+	locations, err := queries.LockProductStock(ctx, productID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if len(locations) == 0 {
+		return nil, nil
+	}
+	service.sortLocations(locations)
+
+	var allocations []*pb.LocationAllocation
+	for _, location := range locations {
+		if location.Quantity >= count {
+			allocations = []*pb.LocationAllocation{
+				{ProductId: productID, LocationId: location.LocationID, Count: count},
+			}
+			break
+		}
+	}
+	if allocations == nil {
+		remaining := count
+		for _, location := range locations {
+			if remaining == 0 {
+				break
+			}
+			take := location.Quantity
+			if take > remaining {
+				take = remaining
+			}
+			if take == 0 {
+				continue
+			}
+			allocations = append(allocations, &pb.LocationAllocation{
+				ProductId:  productID,
+				LocationId: location.LocationID,
+				Count:      take,
+			})
+			remaining -= take
+		}
+		if remaining > 0 {
+			return nil, status.Errorf(codes.Internal, "stock locations of product %d are out of sync", productID)
+		}
+	}
+
+	for _, allocation := range allocations {
+		affected, err := queries.AddProductStock(ctx, repository.AddProductStockParams{
+			Delta:      -allocation.Count,
+			ProductID:  productID,
+			LocationID: allocation.LocationId,
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if affected == 0 {
+			return nil, status.Errorf(codes.Internal, "stock locations of product %d are out of sync", productID)
+		}
+	}
+	return allocations, nil
+}
+
+// fromStock returns how many of the count items that moved the inventory to balance
+// are on hand, the rest were backordered
+func fromStock(count, balance int32) int32 {
+	onHand := balance + count
+	if onHand <= 0 {
+		return 0
+	}
+	if onHand < count {
+		return onHand
+	}
+	return count
+}
+
+// restockLocation puts count items back into a location of the product. When locationID is 0
+// the first location by strategy is used. Products without locations are left alone.
+func (service *ProductService) restockLocation(ctx context.Context, queries *repository.Queries, productID, locationID int64, count int32) error {
+	locations, err := queries.LockProductStock(ctx, productID)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if len(locations) == 0 {
+		if locationID != 0 {
+			return status.Error(codes.FailedPrecondition, "product has no stock location")
+		}
+		return nil
+	}
+	if locationID == 0 {
+		service.sortLocations(locations)
+		locationID = locations[0].LocationID
+	}
+
+	affected, err := queries.AddProductStock(ctx, repository.AddProductStockParams{
+		Delta:      count,
+		ProductID:  productID,
+		LocationID: locationID,
+	})
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if affected == 0 {
+		if count < 0 {
+			return status.Errorf(codes.FailedPrecondition, "location %d doesn't hold %d items", locationID, -count)
+		}
+		return status.Errorf(codes.NotFound, "product has no stock in location %d", locationID)
+	}
+	return nil
+}
+
+// hasLocationStock reports whether the inventory of a product is managed per location
+func hasLocationStock(ctx context.Context, queries *repository.Queries, productID int64) (bool, error) {
+	locations, err := queries.GetProductStock(ctx, productID)
+	if err != nil {
+		return false, err
+	}
+	return len(locations) > 0, nil
+}
+
+// CreateStockLocation adds a warehouse to a supplier
+func (service *ProductService) CreateStockLocation(ctx context.Context, req *pb.CreateStockLocationRequest) (*pb.StockLocation, error) {
+	supplierID := claimedSupplierID(ctx)
+	if len(req.GetName()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "location name is required")
+	}
+	location, err := service.productStore.CreateStockLocation(ctx, repository.CreateStockLocationParams{
+		SupplierID: supplierID,
+		Name:       req.GetName(),
+		Priority:   req.GetPriority(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.StockLocation{
+		LocationId: location.ID,
+		SupplierId: location.SupplierID,
+		Name:       location.Name,
+		Priority:   location.Priority,
+	}, nil
+}
+
+// GetStockLocations lists the warehouses of a supplier
+func (service *ProductService) GetStockLocations(ctx context.Context, req *pb.GetStockLocationsRequest) (*pb.GetStockLocationsResponse, error) {
+	supplierID := claimedSupplierID(ctx)
+	locations, err := service.productStore.GetStockLocationsBySupplier(ctx, supplierID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	result := make([]*pb.StockLocation, 0, len(locations))
+	for _, location := range locations {
+		result = append(result, &pb.StockLocation{
+			LocationId: location.ID,
+			SupplierId: location.SupplierID,
+			Name:       location.Name,
+			Priority:   location.Priority,
+		})
+	}
+
+	return &pb.GetStockLocationsResponse{
+		ListLocation: result,
+	}, nil
+}
+
+// SetLocationStock sets the quantity of a product in a location, the product
+// inventory becomes the sum over its locations
+func (service *ProductService) SetLocationStock(ctx context.Context, req *pb.SetLocationStockRequest) (*pb.GeneralResponse, error) {
+	supplierID := claimedSupplierID(ctx)
+	if req.GetQuantity() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity can't be negative")
+	}
+	if err := service.checkSupplierProducts(ctx, supplierID, req.GetProductId()); err != nil {
+		return nil, err
+	}
+	location, err := service.productStore.GetStockLocation(ctx, req.GetLocationId())
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && location.SupplierID != supplierID) {
+		return nil, status.Error(codes.NotFound, "location not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	oldInventory, err := queries.LockProductInventory(ctx, req.GetProductId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	err = queries.UpsertProductStock(ctx, repository.UpsertProductStockParams{
+		ProductID:  req.GetProductId(),
+		LocationID: location.ID,
+		Quantity:   req.GetQuantity(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	balance, err := queries.SyncProductInventory(ctx, req.GetProductId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	err = recordInventoryChange(ctx, queries, inventoryChange{
+		productID: req.GetProductId(),
+		delta:     balance - oldInventory,
+		balance:   balance,
+		reason:    reasonSupplierUpdate,
+		actor:     supplierActor(supplierID),
+		reference: locationReference(location.ID),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.GeneralResponse{
+		Message: "OK",
+	}, nil
+}
+
+// locationStock returns the per location breakdown of a product inventory
+func (service *ProductService) locationStock(ctx context.Context, productID int64) ([]*pb.LocationStock, error) {
+	locations, err := service.productStore.GetProductStock(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*pb.LocationStock, 0, len(locations))
+	for _, location := range locations {
+		result = append(result, &pb.LocationStock{
+			LocationId: location.LocationID,
+			Name:       location.Name,
+			Quantity:   location.Quantity,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"math"
+	"time"
+
+	"github.com/e-commerce-microservices/product-service/event"
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	productEventBatch = 100
+
+	productWatchPollInterval = 2 * time.Second
+	productWatchBuffer       = 64
+)
+
+// product event metrics, served by expvar on /debug/vars
+var (
+	productEventPending   = expvar.NewInt("product_event_pending")
+	productEventLag       = expvar.NewFloat("product_event_lag_seconds")
+	productEventPublished = expvar.NewInt("product_event_published")
+	productEventFailed    = expvar.NewInt("product_event_failed")
+)
+
+// SetEventPublisher sets where product events are published, without one they only reach
+// the watchers of this instance
+func (service *ProductService) SetEventPublisher(publisher event.Publisher) {
+	service.eventPublisher = publisher
+}
+
+// enqueueEvent stores a product event in the outbox, it must run in the transaction of the
+// change. The event gets its sequence from the relay once committed, see publishEvents.
+func enqueueEvent(ctx context.Context, queries *repository.Queries, productEvent *pb.ProductEvent) error {
+	payload, err := proto.Marshal(productEvent)
+	if err != nil {
+		return err
+	}
+	return queries.EnqueueProductEvent(ctx, repository.EnqueueProductEventParams{
+		ProductID:  productEvent.GetProductId(),
+		SupplierID: productEvent.GetProduct().GetSupplierId(),
+		CategoryID: productEvent.GetProduct().GetCategoryId(),
+		EventType:  productEvent.GetType().String(),
+		Payload:    payload,
+	})
+}
+
+// enqueueProductEvent stores an event carrying the product as it is in the transaction
+func enqueueProductEvent(ctx context.Context, queries *repository.Queries, eventType pb.ProductEventType, productID int64) error {
+	product, err := queries.GetProductByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	return enqueueEvent(ctx, queries, &pb.ProductEvent{
+		Type:      eventType,
+		ProductId: productID,
+		Product:   productMessage(product),
+	})
+}
+
+// stockEventType tells whether the stock on hand ran out or came back,
+// it returns false when neither happened
+func stockEventType(oldInventory, newInventory int32) (pb.ProductEventType, bool) {
+	if oldInventory > 0 && newInventory <= 0 {
+		return pb.ProductEventType_product_out_of_stock, true
+	}
+	if oldInventory <= 0 && newInventory > 0 {
+		return pb.ProductEventType_product_back_in_stock, true
+	}
+	return 0, false
+}
+
+// decodeEvent restores an outbox row into the published event
+func decodeEvent(row repository.ProductEvent) (*pb.ProductEvent, error) {
+	productEvent := &pb.ProductEvent{}
+	if err := proto.Unmarshal(row.Payload, productEvent); err != nil {
+		return nil, err
+	}
+	productEvent.Sequence = row.Sequence.Int64
+	productEvent.OccurredAt = timestamppb.New(row.CreatedAt)
+	return productEvent, nil
+}
+
+// publishEvents gives sequences to the newly committed events and publishes one batch in
+// sequence order. It stops at the first failure, the rest is published on the next run so
+// events never overtake each other.
+//
+// Events are stamped with the id of their transaction. Those older than every running
+// transaction are all committed, and any event committed later has a newer transaction,
+// so numbering them in that order means a sequence is never given to an event after a
+// higher one. Consumers resuming after a sequence never miss an event.
+func (service *ProductService) publishEvents(ctx context.Context) (int, error) {
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	// a single instance numbers and publishes at a time to keep the order
+	locked, err := queries.TryLockProductEventRelay(ctx)
+	if err != nil || !locked {
+		return 0, err
+	}
+	if _, err := queries.AssignProductEventSequences(ctx); err != nil {
+		return 0, err
+	}
+	rows, err := queries.GetUnpublishedProductEvents(ctx, productEventBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	published := make([]*pb.ProductEvent, 0, len(rows))
+	var publishErr error
+	for _, row := range rows {
+		productEvent, err := decodeEvent(row)
+		if err != nil {
+			return 0, err
+		}
+		if service.eventPublisher != nil {
+			if publishErr = service.eventPublisher.Publish(ctx, productEvent); publishErr != nil {
+				productEventFailed.Add(1)
+				break
+			}
+		}
+		productEventPublished.Add(1)
+		published = append(published, productEvent)
+	}
+	if len(published) > 0 {
+		if err := queries.MarkProductEventsPublished(ctx, published[len(published)-1].GetSequence()); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	// wake the watchers of this instance
+	for _, productEvent := range published {
+		service.eventBus.Publish(ctx, productEvent)
+	}
+	return len(published), publishErr
+}
+
+// PublishProductEvents publishes the product events written to the outbox every interval until ctx is done
+func (service *ProductService) PublishProductEvents(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				published, err := service.publishEvents(ctx)
+				if err != nil {
+					log.Println("publish product events error: ", err)
+				}
+				if err != nil || published < productEventBatch {
+					break
+				}
+			}
+
+			lag, err := service.productStore.GetProductEventLag(ctx)
+			if err != nil {
+				log.Println("product event lag error: ", err)
+				continue
+			}
+			productEventPending.Set(lag.Pending)
+			productEventLag.Set(lag.LagSeconds)
+		}
+	}
+}
+
+// WatchProducts streams the product events after req.AfterSequence, from the change log and
+// then as they are committed. Reconnecting with the sequence of the last event received
+// continues without missing one. The change log is polled, events published by this
+// instance are streamed right away.
+func (service *ProductService) WatchProducts(req *pb.WatchProductsRequest, stream pb.ProductService_WatchProductsServer) error {
+	ctx := stream.Context()
+	after := req.GetAfterSequence()
+
+	subscription := service.eventBus.Subscribe(productWatchBuffer)
+	defer func() {
+		subscription.Close()
+	}()
+	ticker := time.NewTicker(productWatchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// sequences are only given to committed events, none will come before the last one
+		last, err := service.productStore.GetLastProductEventSequence(ctx)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		rows, err := service.productStore.GetProductEventsAfter(ctx, repository.GetProductEventsAfterParams{
+			AfterSequence: after,
+			LastSequence:  last,
+			SupplierID:    req.GetSupplierId(),
+			CategoryID:    req.GetCategoryId(),
+			ProductIds:    req.GetListProductId(),
+			RowLimit:      productEventBatch,
+		})
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		for _, row := range rows {
+			productEvent, err := decodeEvent(row)
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+			if err := stream.Send(productEvent); err != nil {
+				return err
+			}
+			after = row.Sequence.Int64
+		}
+		if len(rows) == productEventBatch {
+			continue
+		}
+		// nothing else up to last matches the filters
+		if last > after {
+			after = last
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-subscription.C:
+			if !ok {
+				// dropped for falling behind, the change log has what was missed
+				subscription = service.eventBus.Subscribe(productWatchBuffer)
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyProductChanges puts the products written after a sequence, on any instance, into the
+// suggestion and similarity indexes and returns the sequence of the last event applied
+func (service *ProductService) applyProductChanges(ctx context.Context, after int64) (int64, error) {
+	for {
+		rows, err := service.productStore.GetProductEventsAfter(ctx, repository.GetProductEventsAfterParams{
+			AfterSequence: after,
+			LastSequence:  math.MaxInt64,
+			RowLimit:      productEventBatch,
+		})
+		if err != nil {
+			return after, err
+		}
+		for _, row := range rows {
+			switch row.EventType {
+			case pb.ProductEventType_product_created.String(), pb.ProductEventType_product_updated.String():
+				productEvent, err := decodeEvent(row)
+				if err != nil {
+					return after, err
+				}
+				service.suggestIndex.PutProduct(suggestEventItem(productEvent.GetProduct()))
+				if err := service.followSimilarity(ctx, row.ProductID); err != nil {
+					return after, err
+				}
+			case pb.ProductEventType_product_deleted.String():
+				service.suggestIndex.RemoveProduct(row.ProductID)
+				service.similarityIndex.Remove(row.ProductID)
+			}
+			after = row.Sequence.Int64
+		}
+		if len(rows) < productEventBatch {
+			return after, nil
+		}
+	}
+}
+
+// FollowProductChanges keeps the suggestion and similarity indexes of this instance in step
+// with the others until ctx is done. Product writes come from the change log, categories and
+// popularity of the suggestions are reloaded every interval.
+func (service *ProductService) FollowProductChanges(ctx context.Context, interval time.Duration) {
+	subscription := service.eventBus.Subscribe(productWatchBuffer)
+	defer func() {
+		subscription.Close()
+	}()
+	poll := time.NewTicker(productWatchPollInterval)
+	defer poll.Stop()
+	reload := time.NewTicker(interval)
+	defer reload.Stop()
+
+	after := service.suggestSequence
+	if service.similaritySequence < after {
+		after = service.similaritySequence
+	}
+	for {
+		var err error
+		if after, err = service.applyProductChanges(ctx, after); err != nil {
+			log.Println("follow product changes error: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-subscription.C:
+			if !ok {
+				// dropped for falling behind, the change log has what was missed
+				subscription = service.eventBus.Subscribe(productWatchBuffer)
+			}
+		case <-poll.C:
+		case <-reload.C:
+			if err := service.reloadSuggestRanking(ctx); err != nil {
+				log.Println("reload suggest index error: ", err)
+			}
+		}
+	}
+}
@@ -9,8 +9,11 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/e-commerce-microservices/product-service/event"
 	"github.com/e-commerce-microservices/product-service/pb"
 	"github.com/e-commerce-microservices/product-service/repository"
+	"github.com/e-commerce-microservices/product-service/similarity"
+	"github.com/e-commerce-microservices/product-service/suggest"
 	"github.com/golang/protobuf/ptypes/empty"
 	"go.opentelemetry.io/otel"
 	"google.golang.org/grpc/codes"
@@ -26,20 +29,37 @@ type ProductService struct {
 	imageClient  pb.ImageServiceClient
 	orderClient  pb.OrderServiceClient
 	searchClient pb.SearchServiceClient
+	authClient   pb.AuthServiceClient
 	db           *sql.DB
 
+	similarityIndex    *similarity.Index
+	suggestIndex       *suggest.Index
+	eventPublisher     event.Publisher
+	eventBus           *event.Bus
+	allocationStrategy string
+	serviceTokens      []serviceToken
+	// sequences of the change log the suggestion and similarity indexes were loaded at
+	suggestSequence    int64
+	similaritySequence int64
+
 	pb.UnimplementedProductServiceServer
 }
 
 // NewProductService creates a new ProductService
-func NewProductService(imageClient pb.ImageServiceClient, reviewClient pb.ReviewServiceClient, orderClient pb.OrderServiceClient, searchClient pb.SearchServiceClient, queries *repository.Queries, db *sql.DB) *ProductService {
+func NewProductService(imageClient pb.ImageServiceClient, reviewClient pb.ReviewServiceClient, orderClient pb.OrderServiceClient, searchClient pb.SearchServiceClient, authClient pb.AuthServiceClient, queries *repository.Queries, db *sql.DB) *ProductService {
 	service := &ProductService{
 		orderClient:  orderClient,
 		productStore: queries,
 		reviewClient: reviewClient,
 		imageClient:  imageClient,
 		searchClient: searchClient,
+		authClient:   authClient,
 		db:           db,
+
+		similarityIndex:    similarity.NewIndex(),
+		suggestIndex:       suggest.NewIndex(),
+		eventBus:           event.NewBus(),
+		allocationStrategy: AllocationPriority,
 	}
 
 	return service
@@ -62,13 +82,46 @@ func (service *ProductService) GetCategoryBySupplier(ctx context.Context, req *p
 
 // DeleteProduct ...
 func (service *ProductService) DeleteProduct(ctx context.Context, req *pb.DeleteProductRequest) (*pb.DeleteProductResponse, error) {
-	err := service.productStore.DeleteProduct(ctx, repository.DeleteProductParams{
+	supplierID := claimedSupplierID(ctx)
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	// the deleted event carries the product as it was
+	product, err := queries.GetProductByID(ctx, req.GetProductId())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	affected, err := queries.DeleteProduct(ctx, repository.DeleteProductParams{
 		ID:         req.GetProductId(),
-		SupplierID: req.GetSupplierId(),
+		SupplierID: supplierID,
 	})
 	if err != nil {
 		return nil, err
 	}
+	if affected > 0 {
+		if err := enqueueSearch(ctx, queries, req.GetProductId(), searchDelete); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		err = enqueueEvent(ctx, queries, &pb.ProductEvent{
+			Type:      pb.ProductEventType_product_deleted,
+			ProductId: product.ID,
+			Product:   productMessage(product),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if affected > 0 {
+		service.similarityIndex.Remove(req.GetProductId())
+		service.suggestIndex.RemoveProduct(req.GetProductId())
+	}
 
 	return &pb.DeleteProductResponse{
 		Message: "Xóa sản phẩm thành công",
@@ -77,16 +130,96 @@ func (service *ProductService) DeleteProduct(ctx context.Context, req *pb.Delete
 
 // DeleteProductByAdmin ...
 func (service *ProductService) DeleteProductByAdmin(ctx context.Context, req *pb.DeleteProductByAdminRequest) (*pb.DeleteProductByAdminResponse, error) {
-	err := service.productStore.DeleteProductByID(ctx, req.GetProductId())
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	// the deleted event carries the product as it was
+	product, err := queries.GetProductByID(ctx, req.GetProductId())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	affected, err := queries.DeleteProductByID(ctx, req.GetProductId())
 	if err != nil {
 		return nil, err
 	}
+	if affected > 0 {
+		if err := enqueueSearch(ctx, queries, req.GetProductId(), searchDelete); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		err = enqueueEvent(ctx, queries, &pb.ProductEvent{
+			Type:      pb.ProductEventType_product_deleted,
+			ProductId: product.ID,
+			Product:   productMessage(product),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		err = enqueueWebhook(ctx, queries, &pb.WebhookEvent{
+			Type:       pb.WebhookEventType_webhook_deleted_by_admin,
+			SupplierId: product.SupplierID,
+			Product:    productMessage(product),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if affected > 0 {
+		service.similarityIndex.Remove(req.GetProductId())
+		service.suggestIndex.RemoveProduct(req.GetProductId())
+	}
 
 	return &pb.DeleteProductByAdminResponse{
 		Message: "Xóa sản phẩm thành công",
 	}, nil
 }
 
+// ApproveProduct marks a product approved by an admin and notifies the supplier webhooks,
+// approving it again changes nothing
+func (service *ProductService) ApproveProduct(ctx context.Context, req *pb.ApproveProductRequest) (*pb.GeneralResponse, error) {
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	product, err := queries.GetProductByID(ctx, req.GetProductId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	approved, err := queries.ApproveProduct(ctx, product.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if approved > 0 {
+		err = enqueueWebhook(ctx, queries, &pb.WebhookEvent{
+			Type:       pb.WebhookEventType_webhook_approved,
+			SupplierId: product.SupplierID,
+			Product:    productMessage(product),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.GeneralResponse{
+		Message: "OK",
+	}, nil
+}
+
 // DescInventory ...
 func (service *ProductService) DescInventory(ctx context.Context, req *pb.DescInventoryRequest) (*pb.DescInventoryResponse, error) {
 	// md, _ := metadata.FromIncomingContext(ctx)
@@ -94,32 +227,89 @@ func (service *ProductService) DescInventory(ctx context.Context, req *pb.DescIn
 
 	// ctx, span := otel.Tracer("").Start(ctx, "ProductService.UpdateInventory")
 	// defer span.End()
-	err := service.productStore.DescInventory(ctx, repository.DescInventoryParams{
-		Inventory: req.GetCount(),
-		ID:        req.GetProductId(),
+	if req.GetCount() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "count must be positive")
+	}
+	result := &pb.DescInventoryResponse{}
+	err := service.runIdempotent(ctx, req.GetIdempotencyKey(), "DescInventory", req, result, func(queries *repository.Queries) error {
+		balance, err := queries.DescInventory(ctx, repository.DescInventoryParams{
+			Inventory: req.GetCount(),
+			ID:        req.GetProductId(),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return shortageError(ctx, queries, req.GetProductId(), req.GetCount())
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		err = recordInventoryChange(ctx, queries, inventoryChange{
+			productID: req.GetProductId(),
+			delta:     -req.GetCount(),
+			balance:   balance,
+			reason:    reasonOrderDecrement,
+			actor:     actorOrderService,
+			reference: orderReference(req.GetOrderId()),
+		})
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		result.ListAllocation, err = service.allocateLocations(ctx, queries, req.GetProductId(), req.GetCount(), balance)
+		if err != nil {
+			return err
+		}
+		result.Message = "OK"
+		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &pb.DescInventoryResponse{
-		Message: "OK",
-	}, nil
+	return result, nil
 }
 
 // IncInventory ...
 func (service *ProductService) IncInventory(ctx context.Context, req *pb.IncInventoryRequest) (*pb.IncInventoryResponse, error) {
-	err := service.productStore.IncInventory(ctx, repository.IncInventoryParams{
-		Inventory: req.GetCount(),
-		ID:        req.GetProductId(),
+	if req.GetCount() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "count must be positive")
+	}
+	result := &pb.IncInventoryResponse{}
+	err := service.runIdempotent(ctx, req.GetIdempotencyKey(), "IncInventory", req, result, func(queries *repository.Queries) error {
+		balance, err := queries.IncInventory(ctx, repository.IncInventoryParams{
+			Inventory: req.GetCount(),
+			ID:        req.GetProductId(),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Error(codes.NotFound, "product not found")
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		err = recordInventoryChange(ctx, queries, inventoryChange{
+			productID: req.GetProductId(),
+			delta:     req.GetCount(),
+			balance:   balance,
+			reason:    reasonOrderCancel,
+			actor:     actorOrderService,
+			reference: orderReference(req.GetOrderId()),
+		})
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		// cancelled backorders were never taken from a location
+		restocked := fromStock(req.GetCount(), balance-req.GetCount())
+		if restocked > 0 {
+			if err := service.restockLocation(ctx, queries, req.GetProductId(), req.GetLocationId(), restocked); err != nil {
+				return err
+			}
+		}
+		result.Message = "OK"
+		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &pb.IncInventoryResponse{
-		Message: "OK",
-	}, nil
+	return result, nil
 }
 
 // CreateCategory creates a new Product Category
@@ -135,6 +325,7 @@ func (service *ProductService) CreateCategory(ctx context.Context, req *pb.Creat
 	if err != nil {
 		return nil, status.Error(codes.Canceled, err.Error())
 	}
+	service.suggestIndex.SetCategory(req.GetCategoryId(), req.GetName())
 
 	return &pb.GeneralResponse{
 		Message: "create new category successfull",
@@ -164,6 +355,7 @@ func (service *ProductService) GetListCategory(ctx context.Context, _ *empty.Emp
 
 // CreateProduct ...
 func (service *ProductService) CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.CreateProductResponse, error) {
+	supplierID := claimedSupplierID(ctx)
 	if len(req.GetProductName()) == 0 {
 		return nil, errors.New("Vui lòng điền thông tin tên sản phẩm")
 	}
@@ -175,13 +367,20 @@ func (service *ProductService) CreateProduct(ctx context.Context, req *pb.Create
 	if err != nil {
 		return nil, err
 	}
-	prod, err := service.productStore.CreateProduct(ctx, repository.CreateProductParams{
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	prod, err := queries.CreateProduct(ctx, repository.CreateProductParams{
 		Name:        req.GetProductName(),
 		Description: req.GetDesc(),
 		Price:       req.GetPrice(),
 		Thumbnail:   thumbnail,
 		Inventory:   int32(req.GetInventory()),
-		SupplierID:  req.GetSupplierId(),
+		SupplierID:  supplierID,
 		CategoryID:  req.GetCategoryId(),
 		Brand: sql.NullString{
 			String: req.GetBrand(),
@@ -192,13 +391,38 @@ func (service *ProductService) CreateProduct(ctx context.Context, req *pb.Create
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-
+	err = recordInventoryChange(ctx, queries, inventoryChange{
+		productID: prod.ID,
+		delta:     prod.Inventory,
+		balance:   prod.Inventory,
+		reason:    reasonCreate,
+		actor:     supplierActor(prod.SupplierID),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
 	// add product into es
-	_, err = service.searchClient.AddProduct(ctx, &pb.AddProductRequest{
-		ProductId:   prod.ID,
-		ProductName: prod.Name,
+	if err := enqueueSearch(ctx, queries, prod.ID, searchUpsert); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	err = enqueueEvent(ctx, queries, &pb.ProductEvent{
+		Type:      pb.ProductEventType_product_created,
+		ProductId: prod.ID,
+		Product:   productMessage(prod),
 	})
-	log.Println("insert es error: ", err)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := service.refreshSimilarity(ctx, prod.ID); err != nil {
+		log.Println("refresh similarity error: ", err)
+	}
+	if err := service.refreshSuggest(ctx, prod.ID); err != nil {
+		log.Println("refresh suggest error: ", err)
+	}
 
 	return &pb.CreateProductResponse{
 		Message: "product is created",
@@ -216,18 +440,23 @@ func (service *ProductService) GetProduct(ctx context.Context, req *pb.GetProduc
 		ProductId: product.ID,
 	})
 	if err != nil {
-		return &pb.Product{
-			ProductId:  product.ID,
-			SupplierId: product.SupplierID,
-			CategoryId: product.CategoryID,
-			Name:       product.Name,
-			Desc:       product.Description,
-			Price:      product.Price,
-			Thumbnail:  product.Thumbnail,
-			Inventory:  product.Inventory,
-			CreatedAt:  timestamppb.New(product.CreatedAt),
-			Brand:      product.Brand.String,
-		}, nil
+		result := &pb.Product{
+			ProductId:    product.ID,
+			SupplierId:   product.SupplierID,
+			CategoryId:   product.CategoryID,
+			Name:         product.Name,
+			Desc:         product.Description,
+			Price:        product.Price,
+			Thumbnail:    product.Thumbnail,
+			Inventory:    product.Inventory,
+			StockPolicy:  stockPolicy(product),
+			Availability: availability(product),
+			AvailableAt:  availableAt(product),
+			CreatedAt:    timestamppb.New(product.CreatedAt),
+			Brand:        product.Brand.String,
+		}
+		service.applyFlashSale(ctx, result)
+		return result, nil
 	}
 	var totalStar int32
 	lenTotalStar := 0
@@ -250,20 +479,25 @@ func (service *ProductService) GetProduct(ctx context.Context, req *pb.GetProduc
 		totalSold = int32(soldProductResponse.Count)
 	}
 
-	return &pb.Product{
-		ProductId:   product.ID,
-		SupplierId:  product.SupplierID,
-		CategoryId:  product.CategoryID,
-		Name:        product.Name,
-		Desc:        product.Description,
-		Price:       product.Price,
-		Thumbnail:   product.Thumbnail,
-		Inventory:   product.Inventory,
-		CreatedAt:   timestamppb.New(product.CreatedAt),
-		Brand:       product.Brand.String,
-		TotalSold:   int64(totalSold),
-		StarAverage: starAvg,
-	}, nil
+	result := &pb.Product{
+		ProductId:    product.ID,
+		SupplierId:   product.SupplierID,
+		CategoryId:   product.CategoryID,
+		Name:         product.Name,
+		Desc:         product.Description,
+		Price:        product.Price,
+		Thumbnail:    product.Thumbnail,
+		Inventory:    product.Inventory,
+		StockPolicy:  stockPolicy(product),
+		Availability: availability(product),
+		AvailableAt:  availableAt(product),
+		CreatedAt:    timestamppb.New(product.CreatedAt),
+		Brand:        product.Brand.String,
+		TotalSold:    int64(totalSold),
+		StarAverage:  starAvg,
+	}
+	service.applyFlashSale(ctx, result)
+	return result, nil
 }
 
 // GetListProduct ...
@@ -324,15 +558,18 @@ func (service *ProductService) GetListProduct(ctx context.Context, req *pb.GetLi
 		})
 		if err != nil {
 			result = append(result, &pb.Product{
-				ProductId:  product.ID,
-				SupplierId: product.SupplierID,
-				CategoryId: product.CategoryID,
-				Name:       product.Name,
-				Desc:       product.Description,
-				Price:      product.Price,
-				Thumbnail:  product.Thumbnail,
-				Inventory:  product.Inventory,
-				CreatedAt:  timestamppb.New(product.CreatedAt),
+				ProductId:    product.ID,
+				SupplierId:   product.SupplierID,
+				CategoryId:   product.CategoryID,
+				Name:         product.Name,
+				Desc:         product.Description,
+				Price:        product.Price,
+				Thumbnail:    product.Thumbnail,
+				Inventory:    product.Inventory,
+				StockPolicy:  stockPolicy(product),
+				Availability: availability(product),
+				AvailableAt:  availableAt(product),
+				CreatedAt:    timestamppb.New(product.CreatedAt),
 			})
 		} else {
 			var totalStar int32
@@ -357,17 +594,20 @@ func (service *ProductService) GetListProduct(ctx context.Context, req *pb.GetLi
 			}
 
 			result = append(result, &pb.Product{
-				ProductId:   product.ID,
-				SupplierId:  product.SupplierID,
-				CategoryId:  product.CategoryID,
-				Name:        product.Name,
-				Desc:        product.Description,
-				Price:       product.Price,
-				Thumbnail:   product.Thumbnail,
-				Inventory:   product.Inventory,
-				CreatedAt:   timestamppb.New(product.CreatedAt),
-				StarAverage: starAvg,
-				TotalSold:   totalSold,
+				ProductId:    product.ID,
+				SupplierId:   product.SupplierID,
+				CategoryId:   product.CategoryID,
+				Name:         product.Name,
+				Desc:         product.Description,
+				Price:        product.Price,
+				Thumbnail:    product.Thumbnail,
+				Inventory:    product.Inventory,
+				StockPolicy:  stockPolicy(product),
+				Availability: availability(product),
+				AvailableAt:  availableAt(product),
+				CreatedAt:    timestamppb.New(product.CreatedAt),
+				StarAverage:  starAvg,
+				TotalSold:    totalSold,
 			})
 		}
 	}
@@ -379,13 +619,26 @@ func (service *ProductService) GetListProduct(ctx context.Context, req *pb.GetLi
 
 // GetProductBySupplier ...
 func (service *ProductService) GetProductBySupplier(ctx context.Context, req *pb.GetProductBySupplierRequest) (*pb.GetListProductResponse, error) {
+	supplierID := claimedSupplierID(ctx)
 	var tmp []repository.Product
 	var err error
 
-	if req.GetByTime() {
+	if keyword := strings.TrimSpace(req.GetKeyword()); keyword != "" {
+		tmp, err = service.productStore.SearchProducts(ctx, repository.SearchProductsParams{
+			Query:      keyword,
+			CategoryID: req.GetCategoryId(),
+			SupplierID: supplierID,
+			Sort:       searchSort(req.GetByTime(), req.GetByPriceInc(), req.GetByPriceDesc()),
+			RowLimit:   req.GetLimit(),
+			RowOffset:  req.GetOffset(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if req.GetByTime() {
 		if req.GetCategoryId() == 0 {
 			tmp, err = service.productStore.GetProductBySupplierAndTime(ctx, repository.GetProductBySupplierAndTimeParams{
-				SupplierID: req.GetSupplierId(),
+				SupplierID: supplierID,
 				Limit:      req.GetLimit(),
 				Offset:     req.GetOffset(),
 			})
@@ -394,7 +647,7 @@ func (service *ProductService) GetProductBySupplier(ctx context.Context, req *pb
 			}
 		} else {
 			tmp, err = service.productStore.GetProductBySupplierAndTimeAndCategory(ctx, repository.GetProductBySupplierAndTimeAndCategoryParams{
-				SupplierID: req.GetSupplierId(),
+				SupplierID: supplierID,
 				Limit:      req.GetLimit(),
 				Offset:     req.GetOffset(),
 				CategoryID: req.GetCategoryId(),
@@ -406,7 +659,7 @@ func (service *ProductService) GetProductBySupplier(ctx context.Context, req *pb
 	} else if req.GetByPriceInc() {
 		if req.GetCategoryId() == 0 {
 			tmp, err = service.productStore.GetProductBySupplierAndPriceInc(ctx, repository.GetProductBySupplierAndPriceIncParams{
-				SupplierID: req.GetSupplierId(),
+				SupplierID: supplierID,
 				Limit:      req.GetLimit(),
 				Offset:     req.GetOffset(),
 			})
@@ -415,7 +668,7 @@ func (service *ProductService) GetProductBySupplier(ctx context.Context, req *pb
 			}
 		} else {
 			tmp, err = service.productStore.GetProductBySupplierAndPriceIncAndCategory(ctx, repository.GetProductBySupplierAndPriceIncAndCategoryParams{
-				SupplierID: req.GetSupplierId(),
+				SupplierID: supplierID,
 				Limit:      req.GetLimit(),
 				CategoryID: req.GetCategoryId(),
 				Offset:     req.GetOffset(),
@@ -428,7 +681,7 @@ func (service *ProductService) GetProductBySupplier(ctx context.Context, req *pb
 	} else if req.GetByPriceDesc() {
 		if req.GetCategoryId() == 0 {
 			tmp, err = service.productStore.GetProductBySupplierAndPriceDesc(ctx, repository.GetProductBySupplierAndPriceDescParams{
-				SupplierID: req.GetSupplierId(),
+				SupplierID: supplierID,
 				Limit:      req.GetLimit(),
 				Offset:     req.GetOffset(),
 			})
@@ -437,7 +690,7 @@ func (service *ProductService) GetProductBySupplier(ctx context.Context, req *pb
 			}
 		} else {
 			tmp, err = service.productStore.GetProductBySupplierAndPriceDescAndCategory(ctx, repository.GetProductBySupplierAndPriceDescAndCategoryParams{
-				SupplierID: req.GetSupplierId(),
+				SupplierID: supplierID,
 				Limit:      req.GetLimit(),
 				CategoryID: req.GetCategoryId(),
 				Offset:     req.GetOffset(),
@@ -449,7 +702,7 @@ func (service *ProductService) GetProductBySupplier(ctx context.Context, req *pb
 	} else {
 		if req.GetCategoryId() == 0 {
 			tmp, err = service.productStore.GetProductBySupplier(ctx, repository.GetProductBySupplierParams{
-				SupplierID: req.GetSupplierId(),
+				SupplierID: supplierID,
 				Limit:      req.GetLimit(),
 				Offset:     req.GetOffset(),
 			})
@@ -458,7 +711,7 @@ func (service *ProductService) GetProductBySupplier(ctx context.Context, req *pb
 			}
 		} else {
 			tmp, err = service.productStore.GetProductBySupplierAndCategory(ctx, repository.GetProductBySupplierAndCategoryParams{
-				SupplierID: req.GetSupplierId(),
+				SupplierID: supplierID,
 				Limit:      req.GetLimit(),
 				Offset:     req.GetOffset(),
 				CategoryID: req.GetCategoryId(),
@@ -476,15 +729,18 @@ func (service *ProductService) GetProductBySupplier(ctx context.Context, req *pb
 		})
 		if err != nil {
 			result = append(result, &pb.Product{
-				ProductId:  product.ID,
-				SupplierId: product.SupplierID,
-				CategoryId: product.CategoryID,
-				Name:       product.Name,
-				Desc:       product.Description,
-				Price:      product.Price,
-				Thumbnail:  product.Thumbnail,
-				Inventory:  product.Inventory,
-				CreatedAt:  timestamppb.New(product.CreatedAt),
+				ProductId:    product.ID,
+				SupplierId:   product.SupplierID,
+				CategoryId:   product.CategoryID,
+				Name:         product.Name,
+				Desc:         product.Description,
+				Price:        product.Price,
+				Thumbnail:    product.Thumbnail,
+				Inventory:    product.Inventory,
+				StockPolicy:  stockPolicy(product),
+				Availability: availability(product),
+				AvailableAt:  availableAt(product),
+				CreatedAt:    timestamppb.New(product.CreatedAt),
 			})
 		} else {
 			var totalStar int32
@@ -509,17 +765,20 @@ func (service *ProductService) GetProductBySupplier(ctx context.Context, req *pb
 			}
 
 			result = append(result, &pb.Product{
-				ProductId:   product.ID,
-				SupplierId:  product.SupplierID,
-				CategoryId:  product.CategoryID,
-				Name:        product.Name,
-				Desc:        product.Description,
-				Price:       product.Price,
-				Thumbnail:   product.Thumbnail,
-				Inventory:   product.Inventory,
-				CreatedAt:   timestamppb.New(product.CreatedAt),
-				StarAverage: starAvg,
-				TotalSold:   totalSold,
+				ProductId:    product.ID,
+				SupplierId:   product.SupplierID,
+				CategoryId:   product.CategoryID,
+				Name:         product.Name,
+				Desc:         product.Description,
+				Price:        product.Price,
+				Thumbnail:    product.Thumbnail,
+				Inventory:    product.Inventory,
+				StockPolicy:  stockPolicy(product),
+				Availability: availability(product),
+				AvailableAt:  availableAt(product),
+				CreatedAt:    timestamppb.New(product.CreatedAt),
+				StarAverage:  starAvg,
+				TotalSold:    totalSold,
 			})
 		}
 	}
@@ -531,8 +790,36 @@ func (service *ProductService) GetProductBySupplier(ctx context.Context, req *pb
 
 // UpdateProduct ...
 func (service *ProductService) UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest) (*pb.GeneralResponse, error) {
+	supplierID := claimedSupplierID(ctx)
 	log.Println("update product: ", req)
-	err := service.productStore.UpdateProduct(ctx, repository.UpdateProductParams{
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	oldInventory, err := queries.LockProductInventory(ctx, req.GetProductId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	oldProduct, err := queries.GetProductByID(ctx, req.GetProductId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if int32(req.GetInventory()) != oldInventory {
+		managed, err := hasLocationStock(ctx, queries, req.GetProductId())
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if managed {
+			return nil, status.Error(codes.FailedPrecondition, "inventory is managed per stock location")
+		}
+	}
+	affected, err := queries.UpdateProduct(ctx, repository.UpdateProductParams{
 		ID:        req.GetProductId(),
 		Name:      req.GetName(),
 		Price:     req.GetPrice(),
@@ -541,11 +828,59 @@ func (service *ProductService) UpdateProduct(ctx context.Context, req *pb.Update
 			String: req.GetBrand(),
 			Valid:  false,
 		},
-		SupplierID: req.GetSupplierId(),
+		SupplierID: supplierID,
 	})
 	if err != nil {
 		return nil, err
 	}
+	if affected > 0 {
+		err = recordInventoryChange(ctx, queries, inventoryChange{
+			productID: req.GetProductId(),
+			delta:     int32(req.GetInventory()) - oldInventory,
+			balance:   int32(req.GetInventory()),
+			reason:    reasonSupplierUpdate,
+			actor:     supplierActor(supplierID),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if err := enqueueSearch(ctx, queries, req.GetProductId(), searchUpsert); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		product, err := queries.GetProductByID(ctx, req.GetProductId())
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		err = enqueueEvent(ctx, queries, &pb.ProductEvent{
+			Type:      pb.ProductEventType_product_updated,
+			ProductId: product.ID,
+			Product:   productMessage(product),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if product.Price != oldProduct.Price {
+			err = enqueueEvent(ctx, queries, &pb.ProductEvent{
+				Type:      pb.ProductEventType_product_repriced,
+				ProductId: product.ID,
+				Product:   productMessage(product),
+				OldPrice:  oldProduct.Price,
+			})
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := service.refreshSimilarity(ctx, req.GetProductId()); err != nil {
+		log.Println("refresh similarity error: ", err)
+	}
+	if err := service.refreshSuggest(ctx, req.GetProductId()); err != nil {
+		log.Println("refresh suggest error: ", err)
+	}
 
 	return &pb.GeneralResponse{
 		Message: "Update product success",
@@ -563,7 +898,7 @@ func (service *ProductService) GetListProductByIDs(ctx context.Context, req *pb.
 			ListProduct: []*pb.Product{},
 		}, nil
 	}
-	query := `SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand FROM product WHERE id IN ($1)`
+	query := `SELECT id, name, description, price, thumbnail, inventory, supplier_id, category_id, created_at, brand, reorder_threshold, stock_policy, backorder_limit, preorder_until, available_at, reserved FROM product WHERE id IN ($1)`
 	query = strings.ReplaceAll(query, "$1", strings.Join(ids, ","))
 	listProduct, err := service.productStore.GetListProductByIDs(ctx, query)
 	if err != nil {
@@ -577,15 +912,18 @@ func (service *ProductService) GetListProductByIDs(ctx context.Context, req *pb.
 		})
 		if err != nil {
 			result = append(result, &pb.Product{
-				ProductId:  product.ID,
-				SupplierId: product.SupplierID,
-				CategoryId: product.CategoryID,
-				Name:       product.Name,
-				Desc:       product.Description,
-				Price:      product.Price,
-				Thumbnail:  product.Thumbnail,
-				Inventory:  product.Inventory,
-				CreatedAt:  timestamppb.New(product.CreatedAt),
+				ProductId:    product.ID,
+				SupplierId:   product.SupplierID,
+				CategoryId:   product.CategoryID,
+				Name:         product.Name,
+				Desc:         product.Description,
+				Price:        product.Price,
+				Thumbnail:    product.Thumbnail,
+				Inventory:    product.Inventory,
+				StockPolicy:  stockPolicy(product),
+				Availability: availability(product),
+				AvailableAt:  availableAt(product),
+				CreatedAt:    timestamppb.New(product.CreatedAt),
 			})
 		} else {
 			var totalStar int32
@@ -610,17 +948,20 @@ func (service *ProductService) GetListProductByIDs(ctx context.Context, req *pb.
 			}
 
 			result = append(result, &pb.Product{
-				ProductId:   product.ID,
-				SupplierId:  product.SupplierID,
-				CategoryId:  product.CategoryID,
-				Name:        product.Name,
-				Desc:        product.Description,
-				Price:       product.Price,
-				Thumbnail:   product.Thumbnail,
-				Inventory:   product.Inventory,
-				CreatedAt:   timestamppb.New(product.CreatedAt),
-				StarAverage: starAvg,
-				TotalSold:   totalSold,
+				ProductId:    product.ID,
+				SupplierId:   product.SupplierID,
+				CategoryId:   product.CategoryID,
+				Name:         product.Name,
+				Desc:         product.Description,
+				Price:        product.Price,
+				Thumbnail:    product.Thumbnail,
+				Inventory:    product.Inventory,
+				StockPolicy:  stockPolicy(product),
+				Availability: availability(product),
+				AvailableAt:  availableAt(product),
+				CreatedAt:    timestamppb.New(product.CreatedAt),
+				StarAverage:  starAvg,
+				TotalSold:    totalSold,
 			})
 		}
 	}
@@ -645,9 +986,21 @@ func (service *ProductService) GetListProductInventory(ctx context.Context, req
 	if err != nil {
 		return nil, err
 	}
-	return &pb.GetInventoryResponse{
-		Count: int64(resp),
-	}, nil
+	// stock held by checkout reservations can't be ordered
+	reserved, err := service.productStore.GetReservedInventory(ctx, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+	result := &pb.GetInventoryResponse{
+		Count: int64(resp - reserved),
+	}
+	if req.GetWithLocations() {
+		result.ListLocation, err = service.locationStock(ctx, req.GetProductId())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
 // Ping pong
@@ -706,6 +1059,18 @@ func uploadImage(ctx context.Context, dataChunk string, imageClient pb.ImageServ
 
 // GetRecomendProduct ...
 func (service *ProductService) GetRecomendProduct(ctx context.Context, req *pb.GetRecommendProductRequest) (*pb.GetListProductResponse, error) {
+	if customerID := customerID(ctx, req.GetCustomerId()); customerID != 0 {
+		listProduct, err := service.getPersonalizedProduct(ctx, customerID, req.GetLimit(), req.GetOffset())
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if listProduct != nil {
+			return &pb.GetListProductResponse{
+				ListProduct: listProduct,
+			}, nil
+		}
+	}
+
 	listProductStore, err := service.productStore.GetRecommendProduct(ctx, repository.GetRecommendProductParams{
 		Limit:  req.GetLimit(),
 		Offset: req.GetOffset(),
@@ -720,16 +1085,19 @@ func (service *ProductService) GetRecomendProduct(ctx context.Context, req *pb.G
 		})
 		if err != nil {
 			listProduct = append(listProduct, &pb.Product{
-				SupplierId: product.SupplierID,
-				CategoryId: product.CategoryID,
-				Name:       product.Name,
-				Desc:       product.Description,
-				Price:      product.Price,
-				Thumbnail:  product.Thumbnail,
-				Inventory:  product.Inventory,
-				CreatedAt:  timestamppb.New(product.CreatedAt),
-				ProductId:  product.ID,
-				Brand:      product.Brand.String,
+				SupplierId:   product.SupplierID,
+				CategoryId:   product.CategoryID,
+				Name:         product.Name,
+				Desc:         product.Description,
+				Price:        product.Price,
+				Thumbnail:    product.Thumbnail,
+				Inventory:    product.Inventory,
+				StockPolicy:  stockPolicy(product),
+				Availability: availability(product),
+				AvailableAt:  availableAt(product),
+				CreatedAt:    timestamppb.New(product.CreatedAt),
+				ProductId:    product.ID,
+				Brand:        product.Brand.String,
 			})
 		} else {
 			var totalStar int32
@@ -754,17 +1122,20 @@ func (service *ProductService) GetRecomendProduct(ctx context.Context, req *pb.G
 			}
 
 			listProduct = append(listProduct, &pb.Product{
-				ProductId:   product.ID,
-				SupplierId:  product.SupplierID,
-				CategoryId:  product.CategoryID,
-				Name:        product.Name,
-				Desc:        product.Description,
-				Price:       product.Price,
-				Thumbnail:   product.Thumbnail,
-				Inventory:   product.Inventory,
-				CreatedAt:   timestamppb.New(product.CreatedAt),
-				StarAverage: starAvg,
-				TotalSold:   totalSold,
+				ProductId:    product.ID,
+				SupplierId:   product.SupplierID,
+				CategoryId:   product.CategoryID,
+				Name:         product.Name,
+				Desc:         product.Description,
+				Price:        product.Price,
+				Thumbnail:    product.Thumbnail,
+				Inventory:    product.Inventory,
+				StockPolicy:  stockPolicy(product),
+				Availability: availability(product),
+				AvailableAt:  availableAt(product),
+				CreatedAt:    timestamppb.New(product.CreatedAt),
+				StarAverage:  starAvg,
+				TotalSold:    totalSold,
 			})
 		}
 	}
@@ -773,3 +1144,57 @@ func (service *ProductService) GetRecomendProduct(ctx context.Context, req *pb.G
 		ListProduct: listProduct,
 	}, nil
 }
+
+// productMessage converts a stored product into pb.Product
+func productMessage(product repository.Product) *pb.Product {
+	return &pb.Product{
+		ProductId:    product.ID,
+		SupplierId:   product.SupplierID,
+		CategoryId:   product.CategoryID,
+		Name:         product.Name,
+		Desc:         product.Description,
+		Price:        product.Price,
+		Thumbnail:    product.Thumbnail,
+		Inventory:    product.Inventory,
+		StockPolicy:  stockPolicy(product),
+		Availability: availability(product),
+		AvailableAt:  availableAt(product),
+		CreatedAt:    timestamppb.New(product.CreatedAt),
+		Brand:        product.Brand.String,
+
+		ReorderThreshold: product.ReorderThreshold,
+	}
+}
+
+// productDetail converts a stored product into pb.Product, enriched with its
+// review average and sold count when review-service and order-service respond.
+func (service *ProductService) productDetail(ctx context.Context, product repository.Product) *pb.Product {
+	result := productMessage(product)
+
+	reviewResponse, err := service.reviewClient.GetAllReviewByProductID(ctx, &pb.GetAllReviewByProductIDRequest{
+		ProductId: product.ID,
+	})
+	if err != nil {
+		return result
+	}
+	var totalStar int32
+	lenTotalStar := 0
+	for _, review := range reviewResponse.ListReview {
+		if review.NumStar > 0 {
+			totalStar += review.NumStar
+			lenTotalStar++
+		}
+	}
+	if lenTotalStar > 0 {
+		result.StarAverage = float32(totalStar / int32(lenTotalStar))
+	}
+	soldProductResponse, err := service.orderClient.GetSoldProduct(ctx, &pb.GetSoldProductRequest{
+		ProductId: product.ID,
+	})
+	if err == nil {
+		result.TotalSold = soldProductResponse.Count
+	}
+	service.applyFlashSale(ctx, result)
+
+	return result
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// recommendPoolSize bounds how many in-stock products are scored per request
+	recommendPoolSize = 500
+
+	viewedWeight    = 1.0
+	purchasedWeight = 3.0
+
+	categoryAffinity = 0.5
+	brandAffinity    = 0.3
+	priceAffinity    = 0.2
+)
+
+// customerProfile is the affinity of a customer built from viewed and purchased products
+type customerProfile struct {
+	category map[int64]float64
+	brand    map[string]float64
+	avgPrice float64
+}
+
+func newCustomerProfile(activities []repository.GetCustomerProfileRow) customerProfile {
+	profile := customerProfile{
+		category: make(map[int64]float64),
+		brand:    make(map[string]float64),
+	}
+
+	var total, priceSum float64
+	for _, activity := range activities {
+		weight := viewedWeight
+		if activity.ActivityType == pb.CustomerActivityType_purchased.String() {
+			weight = purchasedWeight
+		}
+		weight *= float64(activity.Hit)
+
+		total += weight
+		priceSum += weight * float64(activity.Price)
+		profile.category[activity.CategoryID] += weight
+		if activity.Brand.Valid && activity.Brand.String != "" {
+			profile.brand[activity.Brand.String] += weight
+		}
+	}
+	if total == 0 {
+		return profile
+	}
+
+	for id := range profile.category {
+		profile.category[id] /= total
+	}
+	for brand := range profile.brand {
+		profile.brand[brand] /= total
+	}
+	profile.avgPrice = priceSum / total
+
+	return profile
+}
+
+func (profile customerProfile) categoryIDs() []int64 {
+	ids := make([]int64, 0, len(profile.category))
+	for id := range profile.category {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// score ranks a product by how close it is to the customer's category, brand and price taste
+func (profile customerProfile) score(product repository.Product) float64 {
+	score := categoryAffinity * profile.category[product.CategoryID]
+	if product.Brand.Valid {
+		score += brandAffinity * profile.brand[product.Brand.String]
+	}
+	if profile.avgPrice > 0 && product.Price > 0 {
+		distance := math.Abs(math.Log(float64(product.Price) / profile.avgPrice))
+		score += priceAffinity / (1 + distance)
+	}
+	return score
+}
+
+// getPersonalizedProduct returns nil when the customer has no activity yet,
+// so the caller can fall back to the generic recommendation
+func (service *ProductService) getPersonalizedProduct(ctx context.Context, customerID int64, limit, offset int32) ([]*pb.Product, error) {
+	activities, err := service.productStore.GetCustomerProfile(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(activities) == 0 {
+		return nil, nil
+	}
+	profile := newCustomerProfile(activities)
+
+	candidates, err := service.productStore.GetRecommendCandidates(ctx, repository.GetRecommendCandidatesParams{
+		CustomerID:  customerID,
+		CategoryIds: profile.categoryIDs(),
+		PoolSize:    recommendPoolSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[int64]float64, len(candidates))
+	for _, product := range candidates {
+		scores[product.ID] = profile.score(product)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return scores[candidates[i].ID] > scores[candidates[j].ID]
+	})
+
+	if offset < 0 {
+		offset = 0
+	}
+	if int(offset) >= len(candidates) {
+		return []*pb.Product{}, nil
+	}
+	candidates = candidates[offset:]
+	if limit > 0 && int(limit) < len(candidates) {
+		candidates = candidates[:limit]
+	}
+
+	listProduct := make([]*pb.Product, 0, len(candidates))
+	for _, product := range candidates {
+		listProduct = append(listProduct, service.productDetail(ctx, product))
+	}
+	return listProduct, nil
+}
+
+// customerID returns the customer verified from the caller's token, or the requested
+// customer when another service calls for them. It returns 0 for anonymous callers, the
+// requested customer can't be trusted from them.
+func customerID(ctx context.Context, requested int64) int64 {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return 0
+	}
+	if claims.Service != "" {
+		return requested
+	}
+	return claims.UserID
+}
+
+// RecordCustomerActivity stores viewed or purchased products into the customer profile
+func (service *ProductService) RecordCustomerActivity(ctx context.Context, req *pb.RecordCustomerActivityRequest) (*pb.GeneralResponse, error) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+	// purchases weigh the most in suggestions, only order-service knows they happened
+	if req.GetActivityType() == pb.CustomerActivityType_purchased && claims.Service == "" {
+		return nil, status.Error(codes.PermissionDenied, "purchases are recorded by order-service")
+	}
+	customerID := customerID(ctx, req.GetCustomerId())
+	if customerID == 0 {
+		return nil, status.Error(codes.InvalidArgument, "customer id is required")
+	}
+	if len(req.GetListProductId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "list product id is empty")
+	}
+
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+
+	queries := service.productStore.WithTx(tx)
+	for _, productID := range req.GetListProductId() {
+		err := queries.UpsertCustomerActivity(ctx, repository.UpsertCustomerActivityParams{
+			CustomerID:   customerID,
+			ProductID:    productID,
+			ActivityType: req.GetActivityType().String(),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	weight := viewedWeight
+	if req.GetActivityType() == pb.CustomerActivityType_purchased {
+		weight = purchasedWeight
+	}
+	for _, productID := range req.GetListProductId() {
+		service.suggestIndex.AddPopularity(productID, weight)
+	}
+
+	return &pb.GeneralResponse{
+		Message: "OK",
+	}, nil
+}
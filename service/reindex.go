@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reindex status
+const (
+	reindexRunning  = "running"
+	reindexFinished = "finished"
+)
+
+const (
+	defaultReindexBatch = 100
+	maxReindexBatch     = 1000
+	defaultReindexRate  = 50
+	maxReindexRate      = 1000
+	reindexAttempts     = 3
+)
+
+// ReindexSearch sends every product to the search service in id order, at most rate_per_second
+// products per second. Progress is checkpointed after each batch and streamed back; a run that
+// stopped is resumed from its checkpoint by passing its reindex_id. A reindex runs on one caller at
+// a time, resuming one that is still running is aborted.
+func (service *ProductService) ReindexSearch(req *pb.ReindexSearchRequest, stream pb.ProductService_ReindexSearchServer) error {
+	ctx := stream.Context()
+	batchSize := req.GetBatchSize()
+	if batchSize <= 0 {
+		batchSize = defaultReindexBatch
+	}
+	if batchSize > maxReindexBatch {
+		batchSize = maxReindexBatch
+	}
+	rate := req.GetRatePerSecond()
+	if rate <= 0 {
+		rate = defaultReindexRate
+	}
+	if rate > maxReindexRate {
+		rate = maxReindexRate
+	}
+
+	var job repository.SearchReindex
+	var err error
+	if req.GetReindexId() != 0 {
+		job, err = service.productStore.GetSearchReindex(ctx, req.GetReindexId())
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Error(codes.NotFound, "reindex not found")
+		}
+	} else {
+		job, err = service.productStore.CreateSearchReindex(ctx)
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	// the lock is held by a connection of its own for the whole run, another caller or
+	// instance resuming the same reindex is refused instead of sending its batches twice
+	conn, err := service.db.Conn(ctx)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer conn.Close()
+	locks := repository.New(conn)
+	locked, err := locks.TryLockSearchReindex(ctx, int32(job.ID))
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if !locked {
+		return status.Errorf(codes.Aborted, "reindex %d is running elsewhere", job.ID)
+	}
+	defer func() {
+		if err := locks.UnlockSearchReindex(context.Background(), int32(job.ID)); err != nil {
+			log.Printf("unlock reindex %d error: %v", job.ID, err)
+		}
+	}()
+	// the run that held the lock may have moved the checkpoint
+	job, err = service.productStore.GetSearchReindex(ctx, job.ID)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	remaining, err := service.productStore.CountProductsAfter(ctx, job.LastProductID)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	progress := &pb.ReindexProgress{
+		ReindexId:     job.ID,
+		Indexed:       job.Indexed,
+		Total:         job.Indexed + remaining,
+		LastProductId: job.LastProductID,
+		Done:          job.Status == reindexFinished,
+	}
+	if err := stream.Send(progress); err != nil || progress.Done {
+		return err
+	}
+
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	defer ticker.Stop()
+
+	for {
+		products, err := service.productStore.GetProductNamesAfter(ctx, repository.GetProductNamesAfterParams{
+			ID:    progress.LastProductId,
+			Limit: batchSize,
+		})
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		for _, product := range products {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+			if err := service.indexProduct(ctx, product); err != nil {
+				return status.Errorf(codes.Unavailable, "index product %d: %v, resume with reindex id %d", product.ID, err, job.ID)
+			}
+			progress.Indexed++
+			progress.LastProductId = product.ID
+		}
+		if len(products) < int(batchSize) {
+			progress.Done = true
+		}
+		if progress.Indexed > progress.Total {
+			progress.Total = progress.Indexed
+		}
+
+		jobStatus := reindexRunning
+		if progress.Done {
+			jobStatus = reindexFinished
+		}
+		err = service.productStore.UpdateSearchReindex(ctx, repository.UpdateSearchReindexParams{
+			ID:            job.ID,
+			LastProductID: progress.LastProductId,
+			Indexed:       progress.Indexed,
+			Status:        jobStatus,
+		})
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := stream.Send(progress); err != nil || progress.Done {
+			return err
+		}
+	}
+}
+
+// indexProduct adds a product to the search index, retrying with a growing delay
+func (service *ProductService) indexProduct(ctx context.Context, product repository.GetProductNamesAfterRow) error {
+	var err error
+	for attempt := int32(0); attempt < reindexAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(searchBackoff(attempt)):
+			}
+		}
+		callCtx, cancel := context.WithTimeout(ctx, searchCallTimeout)
+		_, err = service.searchClient.AddProduct(callCtx, &pb.AddProductRequest{
+			ProductId:   product.ID,
+			ProductName: product.Name,
+		})
+		cancel()
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
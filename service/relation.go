@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultRelatedLimit = 10
+
+// checkSupplierProducts makes sure every product exists and belongs to the supplier
+func (service *ProductService) checkSupplierProducts(ctx context.Context, supplierID int64, productIDs ...int64) error {
+	for _, productID := range productIDs {
+		product, err := service.productStore.GetProductByID(ctx, productID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Errorf(codes.NotFound, "product %d not found", productID)
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if product.SupplierID != supplierID {
+			return status.Errorf(codes.PermissionDenied, "product %d does not belong to supplier", productID)
+		}
+	}
+	return nil
+}
+
+// AddProductRelation links two products of the same supplier
+func (service *ProductService) AddProductRelation(ctx context.Context, req *pb.ProductRelationRequest) (*pb.GeneralResponse, error) {
+	supplierID := claimedSupplierID(ctx)
+	if req.GetRelationType() == pb.ProductRelationType_similar {
+		return nil, status.Error(codes.InvalidArgument, "similar products are suggested automatically")
+	}
+	if req.GetProductId() == req.GetRelatedProductId() {
+		return nil, status.Error(codes.InvalidArgument, "can't link a product to itself")
+	}
+	if err := service.checkSupplierProducts(ctx, supplierID, req.GetProductId(), req.GetRelatedProductId()); err != nil {
+		return nil, err
+	}
+
+	err := service.productStore.CreateProductRelation(ctx, repository.CreateProductRelationParams{
+		ProductID:        req.GetProductId(),
+		RelatedProductID: req.GetRelatedProductId(),
+		RelationType:     req.GetRelationType().String(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.GeneralResponse{
+		Message: "OK",
+	}, nil
+}
+
+// RemoveProductRelation unlinks two products of the same supplier
+func (service *ProductService) RemoveProductRelation(ctx context.Context, req *pb.ProductRelationRequest) (*pb.GeneralResponse, error) {
+	supplierID := claimedSupplierID(ctx)
+	if err := service.checkSupplierProducts(ctx, supplierID, req.GetProductId()); err != nil {
+		return nil, err
+	}
+
+	affected, err := service.productStore.DeleteProductRelation(ctx, repository.DeleteProductRelationParams{
+		ProductID:        req.GetProductId(),
+		RelatedProductID: req.GetRelatedProductId(),
+		RelationType:     req.GetRelationType().String(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if affected == 0 {
+		return nil, status.Error(codes.NotFound, "relation not found")
+	}
+
+	return &pb.GeneralResponse{
+		Message: "OK",
+	}, nil
+}
+
+// GetRelatedProducts returns the merchandiser's links first, then fills up with similar products
+func (service *ProductService) GetRelatedProducts(ctx context.Context, req *pb.GetRelatedProductsRequest) (*pb.GetRelatedProductsResponse, error) {
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultRelatedLimit
+	}
+
+	product, err := service.productStore.GetProductByID(ctx, req.GetProductId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	relations, err := service.productStore.GetProductRelations(ctx, product.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	ids := make([]int64, 0, len(relations))
+	for _, relation := range relations {
+		ids = append(ids, relation.RelatedProductID)
+	}
+	linkedProducts, err := service.productStore.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	productByID := make(map[int64]repository.Product, len(linkedProducts))
+	for _, linked := range linkedProducts {
+		productByID[linked.ID] = linked
+	}
+
+	result := make([]*pb.GetRelatedProductsResponse_RelatedProduct, 0, limit)
+	seen := map[int64]bool{product.ID: true}
+	for _, relation := range relations {
+		if len(result) >= limit {
+			break
+		}
+		linked, ok := productByID[relation.RelatedProductID]
+		if !ok || seen[linked.ID] {
+			continue
+		}
+		seen[linked.ID] = true
+		result = append(result, &pb.GetRelatedProductsResponse_RelatedProduct{
+			Product:      service.productDetail(ctx, linked),
+			RelationType: pb.ProductRelationType(pb.ProductRelationType_value[relation.RelationType]),
+		})
+	}
+
+	// text similarity first, then the same category for products the index can't match
+	if len(result) < limit {
+		similarProducts, err := service.similarProducts(ctx, product.ID, limit+len(seen))
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		result = service.appendSimilar(ctx, result, seen, similarProducts, limit)
+	}
+	if len(result) < limit {
+		similarProducts, err := service.productStore.GetSimilarProductsByCategory(ctx, repository.GetSimilarProductsByCategoryParams{
+			CategoryID: product.CategoryID,
+			ProductID:  product.ID,
+			Brand:      product.Brand.String,
+			Price:      product.Price,
+			Size:       int32(limit + len(seen)),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		result = service.appendSimilar(ctx, result, seen, similarProducts, limit)
+	}
+
+	return &pb.GetRelatedProductsResponse{
+		ListRelatedProduct: result,
+	}, nil
+}
+
+// appendSimilar adds suggestions not already in the result until it reaches limit
+func (service *ProductService) appendSimilar(ctx context.Context, result []*pb.GetRelatedProductsResponse_RelatedProduct, seen map[int64]bool, similarProducts []repository.Product, limit int) []*pb.GetRelatedProductsResponse_RelatedProduct {
+	for _, similar := range similarProducts {
+		if len(result) >= limit {
+			break
+		}
+		if seen[similar.ID] {
+			continue
+		}
+		seen[similar.ID] = true
+		result = append(result, &pb.GetRelatedProductsResponse_RelatedProduct{
+			Product:      service.productDetail(ctx, similar),
+			RelationType: pb.ProductRelationType_similar,
+		})
+	}
+	return result
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// reservation status
+const (
+	reservationActive    = "active"
+	reservationCommitted = "committed"
+	reservationReleased  = "released"
+	reservationExpired   = "expired"
+)
+
+const (
+	defaultReservationTTL = 15 * time.Minute
+	maxReservationTTL     = 24 * time.Hour
+)
+
+// ReserveInventory holds stock for a checkout until the reservation is committed, released or expired
+func (service *ProductService) ReserveInventory(ctx context.Context, req *pb.ReserveInventoryRequest) (*pb.ReserveInventoryResponse, error) {
+	if req.GetCount() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "count must be positive")
+	}
+	ttl := reservationTTL(req.GetTtlSeconds())
+
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	available, err := availableInventory(ctx, queries, req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+	if available < req.GetCount() {
+		return nil, insufficientInventory(req.GetProductId(), req.GetCount(), available)
+	}
+
+	reservation, err := queries.CreateReservation(ctx, repository.CreateReservationParams{
+		ProductID: req.GetProductId(),
+		Quantity:  req.GetCount(),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	err = queries.HoldReservedInventory(ctx, repository.HoldReservedInventoryParams{
+		Quantity: reservation.Quantity,
+		ID:       reservation.ProductID,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.ReserveInventoryResponse{
+		ReservationId: reservation.ID,
+		ExpiresAt:     timestamppb.New(reservation.ExpiresAt),
+	}, nil
+}
+
+// reservationTTL bounds the requested lifetime of a reservation
+func reservationTTL(seconds int32) time.Duration {
+	ttl := time.Duration(seconds) * time.Second
+	if ttl <= 0 {
+		return defaultReservationTTL
+	}
+	if ttl > maxReservationTTL {
+		return maxReservationTTL
+	}
+	return ttl
+}
+
+// availableInventory locks the product row and returns on-hand minus active reservations,
+// plus whatever the stock policy allows to sell beyond it
+func availableInventory(ctx context.Context, queries *repository.Queries, productID int64) (int32, error) {
+	product, err := queries.LockProductStockPolicy(ctx, productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, status.Error(codes.NotFound, "product not found")
+	}
+	if err != nil {
+		return 0, status.Error(codes.Internal, err.Error())
+	}
+	return sellable(product.Inventory-product.Reserved, product.StockPolicy, product.BackorderLimit, product.PreorderUntil), nil
+}
+
+// endReservation moves an active reservation to a final status and gives its quantity back to
+// the product, in the transaction of the caller
+func endReservation(ctx context.Context, queries *repository.Queries, reservation repository.InventoryReservation, reservationStatus string) error {
+	err := queries.UpdateReservationStatus(ctx, repository.UpdateReservationStatusParams{
+		ID:     reservation.ID,
+		Status: reservationStatus,
+	})
+	if err != nil {
+		return err
+	}
+	return queries.ReleaseReservedInventory(ctx, repository.ReleaseReservedInventoryParams{
+		Quantity: reservation.Quantity,
+		ID:       reservation.ProductID,
+	})
+}
+
+// activeReservation locks a reservation, marking it expired when its TTL has passed
+func activeReservation(ctx context.Context, queries *repository.Queries, reservationID int64) (repository.InventoryReservation, error) {
+	reservation, err := queries.GetReservationForUpdate(ctx, reservationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return reservation, status.Error(codes.NotFound, "reservation not found")
+	}
+	if err != nil {
+		return reservation, status.Error(codes.Internal, err.Error())
+	}
+	if reservation.Status == reservationActive && !reservation.ExpiresAt.After(time.Now()) {
+		if err := endReservation(ctx, queries, reservation, reservationExpired); err != nil {
+			return reservation, status.Error(codes.Internal, err.Error())
+		}
+		reservation.Status = reservationExpired
+	}
+	return reservation, nil
+}
+
+// CommitReservation turns a reservation into a permanent decrement of the product inventory
+func (service *ProductService) CommitReservation(ctx context.Context, req *pb.ReservationRequest) (*pb.GeneralResponse, error) {
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	reservation, err := activeReservation(ctx, queries, req.GetReservationId())
+	if err != nil {
+		return nil, err
+	}
+	switch reservation.Status {
+	case reservationCommitted:
+		return &pb.GeneralResponse{
+			Message: "OK",
+		}, nil
+	case reservationActive:
+	default:
+		// persist the expiry found by activeReservation before refusing
+		if err := tx.Commit(); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "reservation is %s", reservation.Status)
+	}
+
+	_, err = service.commitReservation(ctx, queries, reservation, "reservation:"+strconv.FormatInt(reservation.ID, 10))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.GeneralResponse{
+		Message: "OK",
+	}, nil
+}
+
+// commitReservation decrements the inventory by an active reservation and marks it committed
+func (service *ProductService) commitReservation(ctx context.Context, queries *repository.Queries, reservation repository.InventoryReservation, reference string) ([]*pb.LocationAllocation, error) {
+	if err := endReservation(ctx, queries, reservation, reservationCommitted); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	// the reservation no longer counts as reserved, so the regular decrement applies
+	balance, err := queries.DescInventory(ctx, repository.DescInventoryParams{
+		Inventory: reservation.Quantity,
+		ID:        reservation.ProductID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, shortageError(ctx, queries, reservation.ProductID, reservation.Quantity)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	err = recordInventoryChange(ctx, queries, inventoryChange{
+		productID: reservation.ProductID,
+		delta:     -reservation.Quantity,
+		balance:   balance,
+		reason:    reasonReservationCommit,
+		actor:     actorOrderService,
+		reference: reference,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return service.allocateLocations(ctx, queries, reservation.ProductID, reservation.Quantity, balance)
+}
+
+// ReleaseReservation gives the reserved stock back, releasing twice is a no-op
+func (service *ProductService) ReleaseReservation(ctx context.Context, req *pb.ReservationRequest) (*pb.GeneralResponse, error) {
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	reservation, err := activeReservation(ctx, queries, req.GetReservationId())
+	if err != nil {
+		return nil, err
+	}
+	switch reservation.Status {
+	case reservationCommitted:
+		return nil, status.Error(codes.FailedPrecondition, "reservation is already committed")
+	case reservationActive:
+		if err := endReservation(ctx, queries, reservation, reservationReleased); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.GeneralResponse{
+		Message: "OK",
+	}, nil
+}
+
+// reservationSweepBatch bounds the products whose expired reservations are released per sweep
+const reservationSweepBatch = 100
+
+// expireReservations marks the expired reservations of a product and gives their quantity back.
+// The reservations are locked before the product, in the order commit and release lock them.
+func (service *ProductService) expireReservations(ctx context.Context, productID int64) (int, error) {
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	quantities, err := queries.ExpireProductReservations(ctx, productID)
+	if err != nil {
+		return 0, err
+	}
+	var released int32
+	for _, quantity := range quantities {
+		released += quantity
+	}
+	if released > 0 {
+		err = queries.ReleaseReservedInventory(ctx, repository.ReleaseReservedInventoryParams{
+			Quantity: released,
+			ID:       productID,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(quantities), tx.Commit()
+}
+
+// SweepExpiredReservations releases expired reservations every interval until ctx is done.
+// Until then an expired reservation still holds its stock.
+func (service *ProductService) SweepExpiredReservations(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			productIDs, err := service.productStore.GetExpiredReservationProducts(ctx, reservationSweepBatch)
+			if err != nil {
+				log.Println("sweep reservations error: ", err)
+				continue
+			}
+			expired := 0
+			for _, productID := range productIDs {
+				count, err := service.expireReservations(ctx, productID)
+				if err != nil {
+					log.Printf("expire reservations of product %d error: %v", productID, err)
+					continue
+				}
+				expired += count
+			}
+			if expired > 0 {
+				log.Printf("released %d expired reservations", expired)
+			}
+		}
+	}
+}
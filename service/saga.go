@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The deduction of an order follows try / confirm / cancel. Try reserves the items of the order,
+// confirm turns the reservations into inventory decrements and cancel releases them. The state is
+// stored per order, so every step can be retried, and a cancel arriving before its try is recorded
+// so the late try is refused instead of holding stock nobody will confirm.
+
+func deductionResponse(saga repository.InventorySaga) *pb.DeductionResponse {
+	result := &pb.DeductionResponse{
+		Message: "OK",
+		Status:  pb.DeductionStatus(pb.DeductionStatus_value[saga.Status]),
+	}
+	if saga.ExpiresAt.Valid {
+		result.ExpiresAt = timestamppb.New(saga.ExpiresAt.Time)
+	}
+	return result
+}
+
+// deductionHeld tells whether every reservation of a tried order is still active. When one ran
+// out, the active ones are released and all of them dropped, so the order can be reserved again.
+func deductionHeld(ctx context.Context, queries *repository.Queries, orderID int64) (bool, error) {
+	reservations, err := queries.GetOrderReservationsForUpdate(ctx, sql.NullInt64{Int64: orderID, Valid: true})
+	if err != nil {
+		return false, err
+	}
+	held := true
+	for _, reservation := range reservations {
+		if reservation.Status != reservationActive || !reservation.ExpiresAt.After(time.Now()) {
+			held = false
+		}
+	}
+	if held {
+		return true, nil
+	}
+	for _, reservation := range reservations {
+		if reservation.Status != reservationActive {
+			continue
+		}
+		if err := endReservation(ctx, queries, reservation, reservationExpired); err != nil {
+			return false, err
+		}
+	}
+	return false, queries.DeleteOrderReservations(ctx, sql.NullInt64{Int64: orderID, Valid: true})
+}
+
+// TryDeductInventory reserves the items of an order until it is confirmed, cancelled or expired.
+// Retrying a try whose reservations expired reserves the items again.
+func (service *ProductService) TryDeductInventory(ctx context.Context, req *pb.TryDeductInventoryRequest) (*pb.DeductionResponse, error) {
+	if req.GetOrderId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "order id is required")
+	}
+	items, err := mergeInventoryItems(req.GetListItem())
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "list item is empty")
+	}
+
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	claimed, err := queries.ClaimInventorySaga(ctx, repository.ClaimInventorySagaParams{
+		OrderID: req.GetOrderId(),
+		Status:  pb.DeductionStatus_tried.String(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	saga, err := queries.GetInventorySagaForUpdate(ctx, req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if claimed == 0 {
+		switch saga.Status {
+		case pb.DeductionStatus_cancelled.String():
+			return nil, status.Errorf(codes.FailedPrecondition, "order %d is cancelled", req.GetOrderId())
+		case pb.DeductionStatus_confirmed.String():
+			return deductionResponse(saga), nil
+		}
+		held, err := deductionHeld(ctx, queries, saga.OrderID)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if held {
+			return deductionResponse(saga), nil
+		}
+	}
+
+	available, err := lockAvailableInventory(ctx, queries, items)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	var shortages []*pb.InventoryShortage
+	for _, item := range items {
+		count, ok := available[item.ProductId]
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "product %d not found", item.ProductId)
+		}
+		if count < item.Count {
+			shortages = append(shortages, &pb.InventoryShortage{
+				ProductId: item.ProductId,
+				Requested: item.Count,
+				Available: count,
+			})
+		}
+	}
+	if len(shortages) > 0 {
+		// nothing is stored, the order may try again
+		return &pb.DeductionResponse{
+			Message:      "Sản phẩm không đủ số lượng",
+			ListShortage: shortages,
+		}, nil
+	}
+
+	expiresAt := time.Now().Add(reservationTTL(req.GetTtlSeconds()))
+	for _, item := range items {
+		_, err := queries.CreateOrderReservation(ctx, repository.CreateOrderReservationParams{
+			ProductID: item.ProductId,
+			Quantity:  item.Count,
+			ExpiresAt: expiresAt,
+			OrderID:   sql.NullInt64{Int64: req.GetOrderId(), Valid: true},
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		err = queries.HoldReservedInventory(ctx, repository.HoldReservedInventoryParams{
+			Quantity: item.Count,
+			ID:       item.ProductId,
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	saga.ExpiresAt = sql.NullTime{Time: expiresAt, Valid: true}
+	err = queries.UpdateInventorySaga(ctx, repository.UpdateInventorySagaParams{
+		OrderID:   saga.OrderID,
+		Status:    saga.Status,
+		ExpiresAt: saga.ExpiresAt,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return deductionResponse(saga), nil
+}
+
+// ConfirmDeduction decrements the inventory by the items reserved for an order
+func (service *ProductService) ConfirmDeduction(ctx context.Context, req *pb.DeductionRequest) (*pb.DeductionResponse, error) {
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	saga, err := queries.GetInventorySagaForUpdate(ctx, req.GetOrderId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "order %d has no deduction", req.GetOrderId())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	switch saga.Status {
+	case pb.DeductionStatus_confirmed.String():
+		return deductionResponse(saga), nil
+	case pb.DeductionStatus_cancelled.String():
+		return nil, status.Errorf(codes.FailedPrecondition, "order %d is cancelled", req.GetOrderId())
+	}
+
+	reservations, err := queries.GetOrderReservationsForUpdate(ctx, sql.NullInt64{Int64: req.GetOrderId(), Valid: true})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	for _, reservation := range reservations {
+		if reservation.Status != reservationActive || !reservation.ExpiresAt.After(time.Now()) {
+			return nil, expireDeduction(ctx, tx, queries, saga, reservations)
+		}
+	}
+	var allocations []*pb.LocationAllocation
+	for _, reservation := range reservations {
+		allocated, err := service.commitReservation(ctx, queries, reservation, orderReference(req.GetOrderId()))
+		if err != nil {
+			return nil, err
+		}
+		allocations = append(allocations, allocated...)
+	}
+
+	saga.Status = pb.DeductionStatus_confirmed.String()
+	saga.ExpiresAt = sql.NullTime{}
+	err = queries.UpdateInventorySaga(ctx, repository.UpdateInventorySagaParams{
+		OrderID:   saga.OrderID,
+		Status:    saga.Status,
+		ExpiresAt: saga.ExpiresAt,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	result := deductionResponse(saga)
+	result.ListAllocation = allocations
+	return result, nil
+}
+
+// expireDeduction cancels an order whose reservations ran out before the confirm, the items
+// still held are released. It commits tx and returns the error for the confirm.
+func expireDeduction(ctx context.Context, tx *sql.Tx, queries *repository.Queries, saga repository.InventorySaga, reservations []repository.InventoryReservation) error {
+	for _, reservation := range reservations {
+		if reservation.Status != reservationActive {
+			continue
+		}
+		if err := endReservation(ctx, queries, reservation, reservationExpired); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+	err := queries.UpdateInventorySaga(ctx, repository.UpdateInventorySagaParams{
+		OrderID: saga.OrderID,
+		Status:  pb.DeductionStatus_cancelled.String(),
+	})
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if err := tx.Commit(); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return status.Errorf(codes.FailedPrecondition, "deduction of order %d has expired, the order is cancelled", saga.OrderID)
+}
+
+// CancelDeduction releases the items reserved for an order. Cancelling an order that was never
+// tried succeeds and blocks its try.
+func (service *ProductService) CancelDeduction(ctx context.Context, req *pb.DeductionRequest) (*pb.DeductionResponse, error) {
+	if req.GetOrderId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "order id is required")
+	}
+
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	claimed, err := queries.ClaimInventorySaga(ctx, repository.ClaimInventorySagaParams{
+		OrderID: req.GetOrderId(),
+		Status:  pb.DeductionStatus_cancelled.String(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	saga, err := queries.GetInventorySagaForUpdate(ctx, req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if claimed == 0 {
+		switch saga.Status {
+		case pb.DeductionStatus_confirmed.String():
+			return nil, status.Errorf(codes.FailedPrecondition, "order %d is already confirmed", req.GetOrderId())
+		case pb.DeductionStatus_cancelled.String():
+			return deductionResponse(saga), nil
+		}
+
+		reservations, err := queries.GetOrderReservationsForUpdate(ctx, sql.NullInt64{Int64: req.GetOrderId(), Valid: true})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		for _, reservation := range reservations {
+			if reservation.Status != reservationActive {
+				continue
+			}
+			if err := endReservation(ctx, queries, reservation, reservationReleased); err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+		}
+
+		saga.Status = pb.DeductionStatus_cancelled.String()
+		saga.ExpiresAt = sql.NullTime{}
+		err = queries.UpdateInventorySaga(ctx, repository.UpdateInventorySagaParams{
+			OrderID:   saga.OrderID,
+			Status:    saga.Status,
+			ExpiresAt: saga.ExpiresAt,
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return deductionResponse(saga), nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// searchSort maps the listing order flags to the order of the search queries,
+// no flag orders by relevance
+func searchSort(byTime, byPriceInc, byPriceDesc bool) string {
+	switch {
+	case byTime:
+		return "time"
+	case byPriceInc:
+		return "price_inc"
+	case byPriceDesc:
+		return "price_desc"
+	}
+	return ""
+}
+
+// SearchProducts finds products by name, brand and description in postgres, so search
+// keeps working without the search service. Accents are ignored and small typos still
+// match. Results are ranked by relevance unless a listing order is asked for.
+func (service *ProductService) SearchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
+	query := strings.TrimSpace(req.GetQuery())
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	products, err := service.productStore.SearchProducts(ctx, repository.SearchProductsParams{
+		Query:      query,
+		CategoryID: req.GetCategoryId(),
+		SupplierID: req.GetSupplierId(),
+		Brand:      req.GetBrand(),
+		MinPrice:   req.GetMinPrice(),
+		MaxPrice:   req.GetMaxPrice(),
+		Sort:       searchSort(req.GetByTime(), req.GetByPriceInc(), req.GetByPriceDesc()),
+		RowLimit:   limit,
+		RowOffset:  req.GetOffset(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	total, err := service.productStore.CountSearchProducts(ctx, repository.CountSearchProductsParams{
+		Query:      query,
+		CategoryID: req.GetCategoryId(),
+		SupplierID: req.GetSupplierId(),
+		Brand:      req.GetBrand(),
+		MinPrice:   req.GetMinPrice(),
+		MaxPrice:   req.GetMaxPrice(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	result := make([]*pb.Product, 0, len(products))
+	for _, product := range products {
+		result = append(result, service.productDetail(ctx, product))
+	}
+
+	return &pb.SearchProductsResponse{
+		ListProduct: result,
+		Total:       total,
+	}, nil
+}
+
+// SearchAndHydrate asks the search service for matching products and returns them in
+// full, in the order the search service ranked them. Products no longer stored are
+// skipped and the availability and stock filters apply before paginating.
+func (service *ProductService) SearchAndHydrate(ctx context.Context, req *pb.SearchAndHydrateRequest) (*pb.SearchProductsResponse, error) {
+	query := strings.TrimSpace(req.GetQuery())
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	offset := req.GetOffset()
+	if offset < 0 {
+		offset = 0
+	}
+
+	found, err := service.searchClient.SearchProduct(ctx, &pb.SearchProductRequest{
+		ProductName: query,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if len(found.GetListProductId()) == 0 {
+		return &pb.SearchProductsResponse{}, nil
+	}
+
+	products, err := service.productStore.GetProductsByIDs(ctx, found.GetListProductId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	byID := make(map[int64]repository.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+	statuses := make(map[pb.AvailabilityStatus]bool, len(req.GetListAvailability()))
+	for _, availability := range req.GetListAvailability() {
+		statuses[availability] = true
+	}
+
+	matched := make([]repository.Product, 0, len(products))
+	seen := make(map[int64]bool, len(products))
+	for _, id := range found.GetListProductId() {
+		product, ok := byID[id]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if len(statuses) > 0 && !statuses[availability(product)] {
+			continue
+		}
+		if product.Inventory < req.GetMinInventory() {
+			continue
+		}
+		matched = append(matched, product)
+	}
+
+	total := int64(len(matched))
+	if int64(offset) >= total {
+		return &pb.SearchProductsResponse{
+			Total: total,
+		}, nil
+	}
+	page := matched[offset:]
+	if len(page) > int(limit) {
+		page = page[:limit]
+	}
+
+	result := make([]*pb.Product, 0, len(page))
+	for _, product := range page {
+		result = append(result, service.productDetail(ctx, product))
+	}
+
+	return &pb.SearchProductsResponse{
+		ListProduct: result,
+		Total:       total,
+	}, nil
+}
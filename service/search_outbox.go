@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"expvar"
+	"log"
+	"time"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+)
+
+// search outbox operations
+const (
+	searchUpsert = "upsert"
+	searchDelete = "delete"
+)
+
+// search outbox statuses, an entry is dead once every attempt failed
+const (
+	searchEntryPending = "pending"
+	searchEntryDead    = "dead"
+)
+
+const (
+	searchOutboxBatch       = 100
+	searchOutboxMaxAttempts = 20
+	searchOutboxMaxBackoff  = 10 * time.Minute
+	searchCallTimeout       = 5 * time.Second
+
+	// a claimed batch is delivered before its lease runs out, then other instances may retry it
+	searchOutboxLease = searchOutboxBatch * searchCallTimeout * 2
+)
+
+// outbox metrics, served by expvar on /debug/vars
+var (
+	searchOutboxPending   = expvar.NewInt("search_outbox_pending")
+	searchOutboxDead      = expvar.NewInt("search_outbox_dead")
+	searchOutboxLag       = expvar.NewFloat("search_outbox_lag_seconds")
+	searchOutboxDelivered = expvar.NewInt("search_outbox_delivered")
+	searchOutboxFailed    = expvar.NewInt("search_outbox_failed")
+)
+
+// enqueueSearch asks the relay to propagate a product write to the search index,
+// it must run in the transaction of the write
+func enqueueSearch(ctx context.Context, queries *repository.Queries, productID int64, operation string) error {
+	return queries.EnqueueSearchOutbox(ctx, repository.EnqueueSearchOutboxParams{
+		ProductID: productID,
+		Operation: operation,
+	})
+}
+
+// searchBackoff doubles the delay after every failed attempt, from one second up to the max
+func searchBackoff(attempts int32) time.Duration {
+	if attempts >= 10 {
+		return searchOutboxMaxBackoff
+	}
+	backoff := time.Second << uint(attempts)
+	if backoff > searchOutboxMaxBackoff {
+		return searchOutboxMaxBackoff
+	}
+	return backoff
+}
+
+func (service *ProductService) deliverSearch(ctx context.Context, entry repository.SearchOutbox) error {
+	ctx, cancel := context.WithTimeout(ctx, searchCallTimeout)
+	defer cancel()
+
+	if entry.Operation == searchDelete {
+		_, err := service.searchClient.RemoveProduct(ctx, &pb.RemoveProductRequest{
+			ProductId: entry.ProductID,
+		})
+		return err
+	}
+
+	// the search index keeps the current name, not the one at the time of the write
+	product, err := service.productStore.GetProductByID(ctx, entry.ProductID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// deleted since, its delete entry removes it
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = service.searchClient.AddProduct(ctx, &pb.AddProductRequest{
+		ProductId:   product.ID,
+		ProductName: product.Name,
+	})
+	return err
+}
+
+// relaySearchOutbox claims a batch of due entries and delivers them outside of a transaction.
+// Failed ones are retried after a backoff and become dead after the last attempt.
+func (service *ProductService) relaySearchOutbox(ctx context.Context) (int, error) {
+	// other instances skip the claimed entries until their lease runs out
+	entries, err := service.productStore.ClaimSearchOutbox(ctx, repository.ClaimSearchOutboxParams{
+		LeaseUntil: time.Now().Add(searchOutboxLease),
+		RowLimit:   searchOutboxBatch,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, entry := range entries {
+		if deliverErr := service.deliverSearch(ctx, entry); deliverErr != nil {
+			log.Printf("deliver search outbox %d error: %v", entry.ID, deliverErr)
+			searchOutboxFailed.Add(1)
+			entryStatus := searchEntryPending
+			if entry.Attempts+1 >= searchOutboxMaxAttempts {
+				entryStatus = searchEntryDead
+			}
+			err = service.productStore.RetrySearchOutbox(ctx, repository.RetrySearchOutboxParams{
+				ID:            entry.ID,
+				Status:        entryStatus,
+				LastError:     deliverErr.Error(),
+				NextAttemptAt: time.Now().Add(searchBackoff(entry.Attempts)),
+			})
+		} else {
+			searchOutboxDelivered.Add(1)
+			err = service.productStore.DeleteSearchOutbox(ctx, entry.ID)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(entries), nil
+}
+
+// RelaySearchOutbox propagates product writes to the search service every interval until ctx is done
+func (service *ProductService) RelaySearchOutbox(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				relayed, err := service.relaySearchOutbox(ctx)
+				if err != nil {
+					log.Println("relay search outbox error: ", err)
+				}
+				if err != nil || relayed < searchOutboxBatch {
+					break
+				}
+			}
+
+			lag, err := service.productStore.GetSearchOutboxLag(ctx)
+			if err != nil {
+				log.Println("search outbox lag error: ", err)
+				continue
+			}
+			searchOutboxPending.Set(lag.Pending)
+			searchOutboxDead.Set(lag.Dead)
+			searchOutboxLag.Set(lag.LagSeconds)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"github.com/e-commerce-microservices/product-service/similarity"
+	"github.com/e-commerce-microservices/product-service/suggest"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// outboxDB is a database holding only the search outbox, the entries enqueued in a
+// transaction appear when it commits. Other statements affect one row and return none.
+type outboxDB struct {
+	mu      sync.Mutex
+	nextID  int64
+	entries []repository.SearchOutbox
+}
+
+func (db *outboxDB) Connect(context.Context) (driver.Conn, error) { return &outboxConn{db: db}, nil }
+func (db *outboxDB) Driver() driver.Driver                        { return nil }
+
+type outboxConn struct {
+	db      *outboxDB
+	pending []repository.SearchOutbox
+}
+
+func (conn *outboxConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+func (conn *outboxConn) Close() error              { return nil }
+func (conn *outboxConn) Begin() (driver.Tx, error) { return conn, nil }
+
+func (conn *outboxConn) Commit() error {
+	conn.db.mu.Lock()
+	defer conn.db.mu.Unlock()
+	for _, entry := range conn.pending {
+		conn.db.nextID++
+		entry.ID = conn.db.nextID
+		conn.db.entries = append(conn.db.entries, entry)
+	}
+	conn.pending = nil
+	return nil
+}
+
+func (conn *outboxConn) Rollback() error {
+	conn.pending = nil
+	return nil
+}
+
+func (conn *outboxConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	switch {
+	case strings.Contains(query, "name: EnqueueSearchOutbox "):
+		conn.pending = append(conn.pending, repository.SearchOutbox{
+			ProductID: args[0].Value.(int64),
+			Operation: args[1].Value.(string),
+			Status:    searchEntryPending,
+		})
+	case strings.Contains(query, "name: DeleteSearchOutbox "):
+		conn.db.mu.Lock()
+		defer conn.db.mu.Unlock()
+		for i, entry := range conn.db.entries {
+			if entry.ID == args[0].Value.(int64) {
+				conn.db.entries = append(conn.db.entries[:i], conn.db.entries[i+1:]...)
+				break
+			}
+		}
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (conn *outboxConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "name: ClaimSearchOutbox ") {
+		return &outboxRows{}, nil
+	}
+	conn.db.mu.Lock()
+	defer conn.db.mu.Unlock()
+	return &outboxRows{entries: append([]repository.SearchOutbox(nil), conn.db.entries...)}, nil
+}
+
+type outboxRows struct {
+	entries []repository.SearchOutbox
+}
+
+func (rows *outboxRows) Columns() []string {
+	return []string{"id", "product_id", "operation", "attempts", "last_error", "next_attempt_at", "created_at", "status"}
+}
+func (rows *outboxRows) Close() error { return nil }
+
+func (rows *outboxRows) Next(dest []driver.Value) error {
+	if len(rows.entries) == 0 {
+		return io.EOF
+	}
+	entry := rows.entries[0]
+	rows.entries = rows.entries[1:]
+	copy(dest, []driver.Value{entry.ID, entry.ProductID, entry.Operation, int64(entry.Attempts), entry.LastError, time.Now(), time.Now(), entry.Status})
+	return nil
+}
+
+// recordingSearch records the calls the relay makes to search-service
+type recordingSearch struct {
+	pb.SearchServiceClient
+	added   []int64
+	removed []int64
+}
+
+func (search *recordingSearch) AddProduct(ctx context.Context, in *pb.AddProductRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	search.added = append(search.added, in.GetProductId())
+	return &emptypb.Empty{}, nil
+}
+
+func (search *recordingSearch) RemoveProduct(ctx context.Context, in *pb.RemoveProductRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	search.removed = append(search.removed, in.GetProductId())
+	return &emptypb.Empty{}, nil
+}
+
+func TestDeleteReachesSearchRelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		delete func(ctx context.Context, service *ProductService) error
+	}{
+		{
+			name: "supplier delete",
+			delete: func(ctx context.Context, service *ProductService) error {
+				ctx = context.WithValue(ctx, claimsKey{}, Claims{UserID: 7})
+				_, err := service.DeleteProduct(ctx, &pb.DeleteProductRequest{ProductId: 42})
+				return err
+			},
+		},
+		{
+			name: "admin delete",
+			delete: func(ctx context.Context, service *ProductService) error {
+				_, err := service.DeleteProductByAdmin(ctx, &pb.DeleteProductByAdminRequest{ProductId: 42})
+				return err
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outbox := &outboxDB{}
+			db := sql.OpenDB(outbox)
+			defer db.Close()
+			search := &recordingSearch{}
+			service := &ProductService{
+				productStore:    repository.New(db),
+				db:              db,
+				searchClient:    search,
+				similarityIndex: similarity.NewIndex(),
+				suggestIndex:    suggest.NewIndex(),
+			}
+
+			ctx := context.Background()
+			if err := test.delete(ctx, service); err != nil {
+				t.Fatalf("delete error = %v", err)
+			}
+			relayed, err := service.relaySearchOutbox(ctx)
+			if err != nil {
+				t.Fatalf("relaySearchOutbox() error = %v", err)
+			}
+			if relayed != 1 {
+				t.Errorf("relayed %d entries, want 1", relayed)
+			}
+			if !equalIDs(search.removed, []int64{42}) || len(search.added) != 0 {
+				t.Errorf("search-service removed %v and added %v, want only 42 removed", search.removed, search.added)
+			}
+			if len(outbox.entries) != 0 {
+				t.Errorf("outbox keeps %d delivered entries", len(outbox.entries))
+			}
+		})
+	}
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"github.com/e-commerce-microservices/product-service/similarity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultSimilarLimit = 10
+
+// LoadSimilarityIndex computes the vectors of products indexed before and
+// loads every stored vector into memory. The products written from then on are
+// applied by FollowProductChanges.
+func (service *ProductService) LoadSimilarityIndex(ctx context.Context) error {
+	// the change log is replayed from before the vectors are read, applying a write twice is harmless
+	sequence, err := service.productStore.GetLastProductEventSequence(ctx)
+	if err != nil {
+		return err
+	}
+	missing, err := service.productStore.GetProductDocumentsWithoutVector(ctx)
+	if err != nil {
+		return err
+	}
+	for _, doc := range missing {
+		err := service.storeTextVector(ctx, doc.ID, similarity.Document{
+			Name:        doc.Name,
+			Description: doc.Description,
+			Brand:       doc.Brand.String,
+			Category:    doc.CategoryName,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	vectors, err := service.productStore.GetAllProductTextVector(ctx)
+	if err != nil {
+		return err
+	}
+	for _, row := range vectors {
+		var vector similarity.Vector
+		if err := json.Unmarshal(row.Terms, &vector); err != nil {
+			return err
+		}
+		service.similarityIndex.Put(row.ProductID, vector)
+	}
+	service.similaritySequence = sequence
+	log.Printf("similarity index loaded %d products", service.similarityIndex.Len())
+
+	return nil
+}
+
+func (service *ProductService) productDocument(ctx context.Context, productID int64) (similarity.Document, error) {
+	doc, err := service.productStore.GetProductDocument(ctx, productID)
+	if err != nil {
+		return similarity.Document{}, err
+	}
+	return similarity.Document{
+		Name:        doc.Name,
+		Description: doc.Description,
+		Brand:       doc.Brand.String,
+		Category:    doc.CategoryName,
+	}, nil
+}
+
+// refreshSimilarity recomputes the vector of a product after it is created or updated
+func (service *ProductService) refreshSimilarity(ctx context.Context, productID int64) error {
+	doc, err := service.productDocument(ctx, productID)
+	if err != nil {
+		return err
+	}
+	return service.storeTextVector(ctx, productID, doc)
+}
+
+// followSimilarity puts the vector of a product written on any instance into the index of
+// this one, the instance that wrote the product stored the vector
+func (service *ProductService) followSimilarity(ctx context.Context, productID int64) error {
+	doc, err := service.productDocument(ctx, productID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// deleted since, its deleted event follows
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	service.similarityIndex.Put(productID, similarity.Terms(doc))
+	return nil
+}
+
+func (service *ProductService) storeTextVector(ctx context.Context, productID int64, doc similarity.Document) error {
+	vector := similarity.Terms(doc)
+	terms, err := json.Marshal(vector)
+	if err != nil {
+		return err
+	}
+	err = service.productStore.UpsertProductTextVector(ctx, repository.UpsertProductTextVectorParams{
+		ProductID: productID,
+		Terms:     terms,
+	})
+	if err != nil {
+		return err
+	}
+	service.similarityIndex.Put(productID, vector)
+	return nil
+}
+
+// similarProducts loads the products most similar to productID, best match first
+func (service *ProductService) similarProducts(ctx context.Context, productID int64, limit int) ([]repository.Product, error) {
+	matches := service.similarityIndex.Similar(productID, limit)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	ids := make([]int64, 0, len(matches))
+	for _, match := range matches {
+		ids = append(ids, match.ID)
+	}
+	products, err := service.productStore.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	productByID := make(map[int64]repository.Product, len(products))
+	for _, product := range products {
+		productByID[product.ID] = product
+	}
+
+	result := make([]repository.Product, 0, len(products))
+	for _, id := range ids {
+		if product, ok := productByID[id]; ok {
+			result = append(result, product)
+		}
+	}
+	return result, nil
+}
+
+// GetSimilarProducts returns products with the closest name, description, brand and category
+func (service *ProductService) GetSimilarProducts(ctx context.Context, req *pb.GetSimilarProductsRequest) (*pb.GetListProductResponse, error) {
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultSimilarLimit
+	}
+
+	_, err := service.productStore.GetProductByID(ctx, req.GetProductId())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	products, err := service.similarProducts(ctx, req.GetProductId(), limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	result := make([]*pb.Product, 0, len(products))
+	for _, product := range products {
+		result = append(result, service.productDetail(ctx, product))
+	}
+
+	return &pb.GetListProductResponse{
+		ListProduct: result,
+	}, nil
+}
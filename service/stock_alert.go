@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	stockAlertPollInterval = 2 * time.Second
+	stockAlertBatchSize    = 100
+)
+
+// stockAlertType tells whether the inventory went from above the threshold to at or below it,
+// or back. It returns false when the threshold isn't crossed.
+func stockAlertType(threshold, oldInventory, newInventory int32) (pb.StockAlertType, bool) {
+	if threshold <= 0 {
+		return 0, false
+	}
+	if oldInventory > threshold && newInventory <= threshold {
+		return pb.StockAlertType_low_stock, true
+	}
+	if oldInventory <= threshold && newInventory > threshold {
+		return pb.StockAlertType_restocked, true
+	}
+	return 0, false
+}
+
+// thresholdAlertType tells whether moving the threshold put the inventory at or below it,
+// or above it again. It returns false when the inventory stays on the same side.
+func thresholdAlertType(oldThreshold, newThreshold, inventory int32) (pb.StockAlertType, bool) {
+	lowBefore := oldThreshold > 0 && inventory <= oldThreshold
+	lowAfter := newThreshold > 0 && inventory <= newThreshold
+	switch {
+	case lowAfter && !lowBefore:
+		return pb.StockAlertType_low_stock, true
+	case lowBefore && !lowAfter:
+		return pb.StockAlertType_restocked, true
+	}
+	return 0, false
+}
+
+// detectStockAlert stores an alert and notifies the supplier webhooks when a change of
+// inventory crosses the reorder threshold
+func detectStockAlert(ctx context.Context, queries *repository.Queries, productID int64, oldInventory, newInventory int32) error {
+	product, err := queries.GetProductThreshold(ctx, productID)
+	if err != nil {
+		return err
+	}
+	alertType, crossed := stockAlertType(product.ReorderThreshold, oldInventory, newInventory)
+	if !crossed {
+		return nil
+	}
+	return raiseStockAlert(ctx, queries, &pb.StockAlert{
+		ProductId:  productID,
+		SupplierId: product.SupplierID,
+		AlertType:  alertType,
+		Inventory:  newInventory,
+		Threshold:  product.ReorderThreshold,
+	})
+}
+
+// raiseStockAlert stores the alert for the watching dashboards and notifies the supplier webhooks
+func raiseStockAlert(ctx context.Context, queries *repository.Queries, alert *pb.StockAlert) error {
+	err := queries.CreateStockAlert(ctx, repository.CreateStockAlertParams{
+		ProductID:  alert.ProductId,
+		SupplierID: alert.SupplierId,
+		AlertType:  alert.AlertType.String(),
+		Inventory:  alert.Inventory,
+		Threshold:  alert.Threshold,
+	})
+	if err != nil {
+		return err
+	}
+
+	webhookType := pb.WebhookEventType_webhook_low_stock
+	if alert.AlertType == pb.StockAlertType_restocked {
+		webhookType = pb.WebhookEventType_webhook_restocked
+	}
+	alert.CreatedAt = timestamppb.Now()
+	return enqueueWebhook(ctx, queries, &pb.WebhookEvent{
+		Type:       webhookType,
+		SupplierId: alert.SupplierId,
+		StockAlert: alert,
+	})
+}
+
+// SetReorderThreshold sets the inventory at which the supplier wants to be alerted, 0 disables alerts
+func (service *ProductService) SetReorderThreshold(ctx context.Context, req *pb.SetReorderThresholdRequest) (*pb.GeneralResponse, error) {
+	supplierID := claimedSupplierID(ctx)
+	if req.GetThreshold() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "threshold can't be negative")
+	}
+
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	product, err := queries.GetProductThreshold(ctx, req.GetProductId())
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && product.SupplierID != supplierID) {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	_, err = queries.SetReorderThreshold(ctx, repository.SetReorderThresholdParams{
+		ID:               req.GetProductId(),
+		SupplierID:       supplierID,
+		ReorderThreshold: req.GetThreshold(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// moving the threshold past the current stock is a crossing as well, so the watchers
+	// agree with ListLowStockProducts
+	if alertType, crossed := thresholdAlertType(product.ReorderThreshold, req.GetThreshold(), product.Inventory); crossed {
+		err = raiseStockAlert(ctx, queries, &pb.StockAlert{
+			ProductId:  req.GetProductId(),
+			SupplierId: supplierID,
+			AlertType:  alertType,
+			Inventory:  product.Inventory,
+			Threshold:  req.GetThreshold(),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.GeneralResponse{
+		Message: "OK",
+	}, nil
+}
+
+// ListLowStockProducts returns the supplier's products at or below their reorder threshold
+func (service *ProductService) ListLowStockProducts(ctx context.Context, req *pb.ListLowStockProductsRequest) (*pb.GetListProductResponse, error) {
+	supplierID := claimedSupplierID(ctx)
+	products, err := service.productStore.GetLowStockProducts(ctx, supplierID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	result := make([]*pb.Product, 0, len(products))
+	for _, product := range products {
+		result = append(result, service.productDetail(ctx, product))
+	}
+
+	return &pb.GetListProductResponse{
+		ListProduct: result,
+	}, nil
+}
+
+// WatchStockAlerts streams the supplier's stock alerts raised after after_alert_id,
+// a reconnecting dashboard passes the last alert id it received. Alert ids are given
+// before commit, so alerts are streamed in the order of their transaction and only once
+// every older transaction is done; an alert committed late is never behind the resume point.
+// An after_alert_id that isn't one of the supplier's alerts is refused rather than replaying
+// the whole history.
+func (service *ProductService) WatchStockAlerts(req *pb.WatchStockAlertsRequest, stream pb.ProductService_WatchStockAlertsServer) error {
+	ctx := stream.Context()
+	supplierID := claimedSupplierID(ctx)
+	lastID := req.GetAfterAlertId()
+	var lastTxid int64
+	if lastID != 0 {
+		txid, err := service.productStore.GetStockAlertTxid(ctx, repository.GetStockAlertTxidParams{
+			ID:         lastID,
+			SupplierID: supplierID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Errorf(codes.InvalidArgument, "alert %d is unknown, watch from 0 to get every alert", lastID)
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		lastTxid = txid
+	}
+
+	ticker := time.NewTicker(stockAlertPollInterval)
+	defer ticker.Stop()
+
+	for {
+		alerts, err := service.productStore.GetStockAlertsAfter(ctx, repository.GetStockAlertsAfterParams{
+			SupplierID: supplierID,
+			AfterTxid:  lastTxid,
+			AfterID:    lastID,
+			RowLimit:   stockAlertBatchSize,
+		})
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		for _, alert := range alerts {
+			err := stream.Send(&pb.StockAlert{
+				AlertId:    alert.ID,
+				ProductId:  alert.ProductID,
+				SupplierId: alert.SupplierID,
+				AlertType:  pb.StockAlertType(pb.StockAlertType_value[alert.AlertType]),
+				Inventory:  alert.Inventory,
+				Threshold:  alert.Threshold,
+				CreatedAt:  timestamppb.New(alert.CreatedAt),
+			})
+			if err != nil {
+				return err
+			}
+			lastTxid, lastID = alert.Txid, alert.ID
+		}
+		if len(alerts) == stockAlertBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxStockCountRows = 5000
+
+// stockCountRow is one counted quantity of the CSV, row is the line number in the file
+type stockCountRow struct {
+	row        int32
+	productID  int64
+	locationID int64
+	counted    int32
+}
+
+type stockCountKey struct {
+	productID  int64
+	locationID int64
+}
+
+// parseStockCount reads a CSV with a header naming the product_id and quantity columns,
+// and optionally location_id for products stocked per location
+func parseStockCount(data []byte) ([]stockCountRow, []*pb.StockCountError) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, []*pb.StockCountError{{Row: 1, Message: "missing header"}}
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	productColumn, hasProduct := columns["product_id"]
+	quantityColumn, hasQuantity := columns["quantity"]
+	locationColumn, hasLocation := columns["location_id"]
+	if !hasProduct || !hasQuantity {
+		return nil, []*pb.StockCountError{{Row: 1, Message: "header must name product_id and quantity columns"}}
+	}
+
+	var rows []stockCountRow
+	var errs []*pb.StockCountError
+	seen := map[stockCountKey]int32{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		rowErr := func(format string, args ...interface{}) {
+			errs = append(errs, &pb.StockCountError{Row: int32(line), Message: fmt.Sprintf(format, args...)})
+		}
+		if err != nil {
+			rowErr("%v", err)
+			continue
+		}
+		if len(rows)+len(errs) >= maxStockCountRows {
+			return nil, []*pb.StockCountError{{Row: int32(line), Message: fmt.Sprintf("at most %d rows per file", maxStockCountRows)}}
+		}
+
+		field := func(column int) string {
+			if column >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[column])
+		}
+		productID, err := strconv.ParseInt(field(productColumn), 10, 64)
+		if err != nil || productID <= 0 {
+			rowErr("invalid product_id %q", field(productColumn))
+			continue
+		}
+		counted, err := strconv.ParseInt(field(quantityColumn), 10, 32)
+		if err != nil || counted < 0 {
+			rowErr("invalid quantity %q", field(quantityColumn))
+			continue
+		}
+		var locationID int64
+		if hasLocation && field(locationColumn) != "" {
+			locationID, err = strconv.ParseInt(field(locationColumn), 10, 64)
+			if err != nil || locationID <= 0 {
+				rowErr("invalid location_id %q", field(locationColumn))
+				continue
+			}
+		}
+
+		key := stockCountKey{productID: productID, locationID: locationID}
+		if first, ok := seen[key]; ok {
+			rowErr("product %d is already counted in row %d", productID, first)
+			continue
+		}
+		seen[key] = int32(line)
+		rows = append(rows, stockCountRow{
+			row:        int32(line),
+			productID:  productID,
+			locationID: locationID,
+			counted:    int32(counted),
+		})
+	}
+	if len(rows) == 0 && len(errs) == 0 {
+		errs = append(errs, &pb.StockCountError{Row: 1, Message: "file has no rows"})
+	}
+	return rows, errs
+}
+
+// previewStockCount compares the counted quantities with the current stock. For a product
+// the current stock is what is on hand, backorders waiting for stock are kept.
+func previewStockCount(ctx context.Context, queries *repository.Queries, supplierID int64, rows []stockCountRow) ([]*pb.StockCountLine, []*pb.StockCountError, error) {
+	ids := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.productID)
+	}
+	products, err := queries.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	inventory := make(map[int64]int32, len(products))
+	for _, product := range products {
+		if product.SupplierID == supplierID {
+			inventory[product.ID] = product.Inventory
+		}
+	}
+
+	locations := map[int64]map[int64]int32{}
+	var lines []*pb.StockCountLine
+	var errs []*pb.StockCountError
+	for _, row := range rows {
+		current, ok := inventory[row.productID]
+		if !ok {
+			errs = append(errs, &pb.StockCountError{Row: row.row, Message: fmt.Sprintf("product %d not found", row.productID)})
+			continue
+		}
+
+		stock, ok := locations[row.productID]
+		if !ok {
+			productStock, err := queries.GetProductStock(ctx, row.productID)
+			if err != nil {
+				return nil, nil, err
+			}
+			stock = make(map[int64]int32, len(productStock))
+			for _, location := range productStock {
+				stock[location.LocationID] = location.Quantity
+			}
+			locations[row.productID] = stock
+		}
+		switch {
+		case row.locationID == 0 && len(stock) > 0:
+			errs = append(errs, &pb.StockCountError{Row: row.row, Message: fmt.Sprintf("product %d is stocked per location, location_id is required", row.productID)})
+			continue
+		case row.locationID != 0:
+			quantity, ok := stock[row.locationID]
+			if !ok {
+				errs = append(errs, &pb.StockCountError{Row: row.row, Message: fmt.Sprintf("product %d has no stock in location %d", row.productID, row.locationID)})
+				continue
+			}
+			current = quantity
+		case current < 0:
+			current = 0
+		}
+
+		lines = append(lines, &pb.StockCountLine{
+			Row:        row.row,
+			ProductId:  row.productID,
+			LocationId: row.locationID,
+			Counted:    row.counted,
+			Current:    current,
+			Delta:      row.counted - current,
+		})
+	}
+	return lines, errs, nil
+}
+
+// ImportStockCount validates a stock count CSV and previews the differences with the current stock.
+// With apply, every difference is recorded as a stock count adjustment, all or nothing.
+func (service *ProductService) ImportStockCount(ctx context.Context, req *pb.ImportStockCountRequest) (*pb.ImportStockCountResponse, error) {
+	supplierID := claimedSupplierID(ctx)
+	if err := checkNote(req.GetNote()); err != nil {
+		return nil, err
+	}
+	rows, errs := parseStockCount(req.GetCsv())
+	if len(errs) > 0 {
+		return &pb.ImportStockCountResponse{
+			ListError: errs,
+		}, nil
+	}
+
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer tx.Rollback()
+	queries := service.productStore.WithTx(tx)
+
+	if req.GetApply() {
+		// every stock change locks the product row, so the preview can't go stale before it is applied
+		ids := make([]int64, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.productID)
+		}
+		if _, err := queries.LockProductsInventory(ctx, ids); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	lines, errs, err := previewStockCount(ctx, queries, supplierID, rows)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	result := &pb.ImportStockCountResponse{
+		ListLine:  lines,
+		ListError: errs,
+	}
+	if !req.GetApply() || len(errs) > 0 {
+		return result, nil
+	}
+
+	for _, line := range lines {
+		if line.Delta == 0 {
+			continue
+		}
+		err := service.adjustInventory(ctx, queries, line.ProductId, line.LocationId, line.Delta, reasonStockCount, supplierActor(supplierID), "", req.GetNote())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	result.Applied = true
+
+	return result, nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// sellable returns how many items can still be sold out of available, the on-hand inventory
+// minus active reservations. It must agree with the allowance in the DescInventory query.
+func sellable(available int32, policy string, backorderLimit int32, preorderUntil sql.NullTime) int32 {
+	switch policy {
+	case pb.StockPolicy_backorder.String():
+		if int64(available)+int64(backorderLimit) > math.MaxInt32 {
+			return math.MaxInt32
+		}
+		return available + backorderLimit
+	case pb.StockPolicy_preorder.String():
+		if preorderUntil.Valid && preorderUntil.Time.After(time.Now()) {
+			return math.MaxInt32
+		}
+	}
+	return available
+}
+
+// availability tells a shopper whether the product ships now, later or not at all
+func availability(product repository.Product) pb.AvailabilityStatus {
+	available := sellable(product.Inventory, product.StockPolicy, product.BackorderLimit, product.PreorderUntil)
+	return availabilityStatus(product.Inventory, available, product.StockPolicy)
+}
+
+func availabilityStatus(onHand, available int32, policy string) pb.AvailabilityStatus {
+	if onHand > 0 {
+		return pb.AvailabilityStatus_in_stock
+	}
+	if available <= 0 {
+		return pb.AvailabilityStatus_out_of_stock
+	}
+	if policy == pb.StockPolicy_preorder.String() {
+		return pb.AvailabilityStatus_preordered
+	}
+	return pb.AvailabilityStatus_backordered
+}
+
+// availableAt is the expected availability date of a product sold without stock on hand
+func availableAt(product repository.Product) *timestamppb.Timestamp {
+	if product.Inventory > 0 || !product.AvailableAt.Valid {
+		return nil
+	}
+	return timestamppb.New(product.AvailableAt.Time)
+}
+
+func stockPolicy(product repository.Product) pb.StockPolicy {
+	return pb.StockPolicy(pb.StockPolicy_value[product.StockPolicy])
+}
+
+func nullTime(timestamp *timestamppb.Timestamp) sql.NullTime {
+	if timestamp == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{
+		Time:  timestamp.AsTime(),
+		Valid: true,
+	}
+}
+
+// SetStockPolicy decides what happens once a product runs out of stock: refuse orders, accept
+// up to backorder_limit backorders, or accept pre-orders until preorder_until
+func (service *ProductService) SetStockPolicy(ctx context.Context, req *pb.SetStockPolicyRequest) (*pb.GeneralResponse, error) {
+	supplierID := claimedSupplierID(ctx)
+	params := repository.SetStockPolicyParams{
+		ID:          req.GetProductId(),
+		SupplierID:  supplierID,
+		StockPolicy: req.GetPolicy().String(),
+		AvailableAt: nullTime(req.GetAvailableAt()),
+	}
+	switch req.GetPolicy() {
+	case pb.StockPolicy_deny:
+	case pb.StockPolicy_backorder:
+		if req.GetBackorderLimit() <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "backorder limit must be positive")
+		}
+		params.BackorderLimit = req.GetBackorderLimit()
+	case pb.StockPolicy_preorder:
+		if req.GetPreorderUntil() == nil || !req.GetPreorderUntil().AsTime().After(time.Now()) {
+			return nil, status.Error(codes.InvalidArgument, "preorder until must be in the future")
+		}
+		params.PreorderUntil = nullTime(req.GetPreorderUntil())
+	default:
+		return nil, status.Error(codes.InvalidArgument, "unknown stock policy")
+	}
+
+	affected, err := service.productStore.SetStockPolicy(ctx, params)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if affected == 0 {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+
+	return &pb.GeneralResponse{
+		Message: "OK",
+	}, nil
+}
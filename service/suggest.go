@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"github.com/e-commerce-microservices/product-service/suggest"
+)
+
+const (
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 50
+)
+
+var suggestionTypes = map[suggest.Kind]pb.SuggestionType{
+	suggest.Product:  pb.SuggestionType_product,
+	suggest.Brand:    pb.SuggestionType_brand,
+	suggest.Category: pb.SuggestionType_category,
+}
+
+func suggestItem(product repository.Product) suggest.Item {
+	return suggest.Item{
+		ID:         product.ID,
+		Name:       product.Name,
+		Brand:      product.Brand.String,
+		CategoryID: product.CategoryID,
+	}
+}
+
+func suggestEventItem(product *pb.Product) suggest.Item {
+	return suggest.Item{
+		ID:         product.GetProductId(),
+		Name:       product.GetName(),
+		Brand:      product.GetBrand(),
+		CategoryID: product.GetCategoryId(),
+	}
+}
+
+// LoadSuggestIndex loads every category, product and product popularity into the prefix index.
+// The products written from then on are applied by FollowProductChanges.
+func (service *ProductService) LoadSuggestIndex(ctx context.Context) error {
+	// the change log is replayed from before the products are read, applying a write twice is harmless
+	sequence, err := service.productStore.GetLastProductEventSequence(ctx)
+	if err != nil {
+		return err
+	}
+	products, err := service.productStore.GetAllProduct(ctx)
+	if err != nil {
+		return err
+	}
+
+	items := make([]suggest.Item, 0, len(products))
+	for _, product := range products {
+		items = append(items, suggestItem(product))
+	}
+	service.suggestIndex.PutProducts(items)
+	if err := service.reloadSuggestRanking(ctx); err != nil {
+		return err
+	}
+	service.suggestSequence = sequence
+	log.Printf("suggest index loaded %d products", service.suggestIndex.Len())
+
+	return nil
+}
+
+// reloadSuggestRanking puts the stored categories and product popularity into the prefix
+// index, other instances change them too
+func (service *ProductService) reloadSuggestRanking(ctx context.Context) error {
+	categories, err := service.productStore.GetAllCategory(ctx)
+	if err != nil {
+		return err
+	}
+	popularity, err := service.productStore.GetProductPopularity(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, category := range categories {
+		service.suggestIndex.SetCategory(category.ID, category.Name)
+	}
+	for _, row := range popularity {
+		service.suggestIndex.SetPopularity(row.ProductID, float64(row.Viewed)*viewedWeight+float64(row.Purchased)*purchasedWeight)
+	}
+	return nil
+}
+
+// refreshSuggest puts the stored name and brand of a product into the prefix index
+func (service *ProductService) refreshSuggest(ctx context.Context, productID int64) error {
+	product, err := service.productStore.GetProductByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	service.suggestIndex.PutProduct(suggestItem(product))
+	return nil
+}
+
+// SuggestProducts completes a typed prefix with product names, brands and categories,
+// most viewed and purchased first. It is served from memory and is cheap enough to be
+// called on every keystroke.
+func (service *ProductService) SuggestProducts(ctx context.Context, req *pb.SuggestProductsRequest) (*pb.SuggestProductsResponse, error) {
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultSuggestLimit
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+
+	suggestions := service.suggestIndex.Suggest(req.GetPrefix(), limit)
+	result := make([]*pb.Suggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		result = append(result, &pb.Suggestion{
+			Type: suggestionTypes[suggestion.Kind],
+			Text: suggestion.Text,
+			Id:   suggestion.ID,
+		})
+	}
+
+	return &pb.SuggestProductsResponse{
+		ListSuggestion: result,
+	}, nil
+}
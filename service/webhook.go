@@ -0,0 +1,452 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/e-commerce-microservices/product-service/pb"
+	"github.com/e-commerce-microservices/product-service/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	webhookBatch       = 50
+	webhookTimeout     = 10 * time.Second
+	webhookMaxAttempts = 10
+	webhookMaxBackoff  = 6 * time.Hour
+	// endpoints served at the same time, each one gets its deliveries in order
+	webhookConcurrency = 8
+
+	// a claimed batch is delivered before its lease runs out, then other instances may retry it
+	webhookLease = webhookBatch * webhookTimeout * 2
+
+	defaultWebhookDeliveryLimit = 20
+	maxWebhookDeliveryLimit     = 100
+)
+
+// webhookClient only connects to public addresses, so a supplier URL can't reach the services
+// inside the cluster. The address is checked when dialing as well as at registration, a host
+// resolving to a public address first and to an internal one later is still refused.
+var webhookClient = &http.Client{
+	Timeout: webhookTimeout,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: webhookTimeout,
+			Control: func(_, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+					return fmt.Errorf("webhook address %s is not public", host)
+				}
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout: webhookTimeout,
+		MaxIdleConnsPerHost: 2,
+	},
+	// the receiver must answer itself, a redirect could point anywhere
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// reservedNets are the ranges not covered by the net.IP checks that aren't reachable on the
+// internet either
+var reservedNets = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("192.0.0.0/24"),
+	mustParseCIDR("198.18.0.0/15"),
+	mustParseCIDR("240.0.0.0/4"),
+	mustParseCIDR("64:ff9b::/96"),
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// publicIP rejects loopback, private, link-local (the cloud metadata address among them),
+// multicast and reserved addresses
+func publicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, network := range reservedNets {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkWebhookURL accepts https URLs whose host only resolves to public addresses
+func checkWebhookURL(ctx context.Context, rawURL string) (*url.URL, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || target.Scheme != "https" || target.Hostname() == "" || target.User != nil {
+		return nil, status.Error(codes.InvalidArgument, "url must be an absolute https URL")
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, target.Hostname())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "url host can't be resolved: %v", err)
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return nil, status.Error(codes.InvalidArgument, "url must not point to an internal address")
+		}
+	}
+	return target, nil
+}
+
+var webhookJSON = protojson.MarshalOptions{UseProtoNames: true}
+
+// webhookBackoff doubles the delay after every failed attempt, from 30 seconds up to the max
+func webhookBackoff(attempts int32) time.Duration {
+	if attempts >= 16 {
+		return webhookMaxBackoff
+	}
+	backoff := 30 * time.Second << uint(attempts)
+	if backoff > webhookMaxBackoff {
+		return webhookMaxBackoff
+	}
+	return backoff
+}
+
+// signWebhook is the hex HMAC-SHA256 of "<timestamp>.<body>" with the webhook secret. Receivers
+// recompute it and reject old timestamps, so a captured request can't be replayed later.
+func signWebhook(secret string, timestamp int64, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "." + body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func webhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// enqueueWebhook schedules a delivery to every webhook of the supplier subscribed to the event,
+// it must run in the transaction of the change
+func enqueueWebhook(ctx context.Context, queries *repository.Queries, webhookEvent *pb.WebhookEvent) error {
+	webhookEvent.OccurredAt = timestamppb.Now()
+	payload, err := webhookJSON.Marshal(webhookEvent)
+	if err != nil {
+		return err
+	}
+	return queries.EnqueueWebhookDeliveries(ctx, repository.EnqueueWebhookDeliveriesParams{
+		EventType:  webhookEvent.GetType().String(),
+		Payload:    string(payload),
+		SupplierID: webhookEvent.GetSupplierId(),
+	})
+}
+
+func webhookMessage(subscription repository.WebhookSubscription) *pb.Webhook {
+	eventTypes := make([]pb.WebhookEventType, 0, len(subscription.EventTypes))
+	for _, eventType := range subscription.EventTypes {
+		eventTypes = append(eventTypes, pb.WebhookEventType(pb.WebhookEventType_value[eventType]))
+	}
+	return &pb.Webhook{
+		WebhookId:     subscription.ID,
+		SupplierId:    subscription.SupplierID,
+		Url:           subscription.Url,
+		ListEventType: eventTypes,
+		CreatedAt:     timestamppb.New(subscription.CreatedAt),
+	}
+}
+
+// CreateWebhook subscribes a supplier URL to product events. Deliveries are JSON POSTs signed
+// with the secret, which is only returned here.
+func (service *ProductService) CreateWebhook(ctx context.Context, req *pb.CreateWebhookRequest) (*pb.Webhook, error) {
+	supplierID := claimedSupplierID(ctx)
+	target, err := checkWebhookURL(ctx, req.GetUrl())
+	if err != nil {
+		return nil, err
+	}
+	if len(req.GetListEventType()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "list event type is empty")
+	}
+	eventTypes := make([]string, 0, len(req.GetListEventType()))
+	seen := make(map[pb.WebhookEventType]bool)
+	for _, eventType := range req.GetListEventType() {
+		if _, ok := pb.WebhookEventType_name[int32(eventType)]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown event type %d", eventType)
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			eventTypes = append(eventTypes, eventType.String())
+		}
+	}
+	secret := req.GetSecret()
+	if secret == "" {
+		secret, err = webhookSecret()
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	subscription, err := service.productStore.CreateWebhookSubscription(ctx, repository.CreateWebhookSubscriptionParams{
+		SupplierID: supplierID,
+		Url:        target.String(),
+		EventTypes: eventTypes,
+		Secret:     secret,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	webhook := webhookMessage(subscription)
+	webhook.Secret = subscription.Secret
+	return webhook, nil
+}
+
+// ListWebhooks returns the webhooks of a supplier without their secrets
+func (service *ProductService) ListWebhooks(ctx context.Context, req *pb.ListWebhooksRequest) (*pb.ListWebhooksResponse, error) {
+	supplierID := claimedSupplierID(ctx)
+	subscriptions, err := service.productStore.GetWebhookSubscriptions(ctx, supplierID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	result := make([]*pb.Webhook, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		result = append(result, webhookMessage(subscription))
+	}
+
+	return &pb.ListWebhooksResponse{
+		ListWebhook: result,
+	}, nil
+}
+
+// DeleteWebhook unsubscribes a webhook, its pending and dead deliveries are dropped
+func (service *ProductService) DeleteWebhook(ctx context.Context, req *pb.DeleteWebhookRequest) (*pb.GeneralResponse, error) {
+	supplierID := claimedSupplierID(ctx)
+	affected, err := service.productStore.DeleteWebhookSubscription(ctx, repository.DeleteWebhookSubscriptionParams{
+		ID:         req.GetWebhookId(),
+		SupplierID: supplierID,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if affected == 0 {
+		return nil, status.Error(codes.NotFound, "webhook not found")
+	}
+
+	return &pb.GeneralResponse{
+		Message: "OK",
+	}, nil
+}
+
+// ListDeadWebhookDeliveries returns the deliveries given up after every retry failed, newest first
+func (service *ProductService) ListDeadWebhookDeliveries(ctx context.Context, req *pb.ListDeadWebhookDeliveriesRequest) (*pb.ListDeadWebhookDeliveriesResponse, error) {
+	supplierID := claimedSupplierID(ctx)
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = defaultWebhookDeliveryLimit
+	}
+	if limit > maxWebhookDeliveryLimit {
+		limit = maxWebhookDeliveryLimit
+	}
+	deliveries, err := service.productStore.GetDeadWebhookDeliveries(ctx, repository.GetDeadWebhookDeliveriesParams{
+		SupplierID:     supplierID,
+		SubscriptionID: req.GetWebhookId(),
+		RowLimit:       limit,
+		RowOffset:      req.GetOffset(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	result := make([]*pb.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, &pb.WebhookDelivery{
+			DeliveryId:     delivery.ID,
+			WebhookId:      delivery.SubscriptionID,
+			EventType:      pb.WebhookEventType(pb.WebhookEventType_value[delivery.EventType]),
+			Payload:        delivery.Payload,
+			Status:         pb.WebhookDeliveryStatus(pb.WebhookDeliveryStatus_value[delivery.Status]),
+			Attempts:       delivery.Attempts,
+			ResponseStatus: delivery.ResponseStatus,
+			LastError:      delivery.LastError,
+			CreatedAt:      timestamppb.New(delivery.CreatedAt),
+		})
+	}
+
+	return &pb.ListDeadWebhookDeliveriesResponse{
+		ListDelivery: result,
+	}, nil
+}
+
+// ReplayWebhookDeliveries sends dead deliveries again with a fresh set of retries
+func (service *ProductService) ReplayWebhookDeliveries(ctx context.Context, req *pb.ReplayWebhookDeliveriesRequest) (*pb.ReplayWebhookDeliveriesResponse, error) {
+	supplierID := claimedSupplierID(ctx)
+	replayed, err := service.productStore.ReplayWebhookDeliveries(ctx, repository.ReplayWebhookDeliveriesParams{
+		SupplierID:     supplierID,
+		SubscriptionID: req.GetWebhookId(),
+		Ids:            req.GetListDeliveryId(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.ReplayWebhookDeliveriesResponse{
+		Replayed: replayed,
+	}, nil
+}
+
+// postWebhook sends one delivery, it returns the response status even when it failed
+func postWebhook(ctx context.Context, delivery repository.ClaimWebhookDeliveriesRow) (int32, error) {
+	if target, err := url.Parse(delivery.Url); err != nil || target.Scheme != "https" {
+		return 0, fmt.Errorf("webhook url %q is not https", delivery.Url)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Webhook-Id", strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set("X-Webhook-Event", delivery.EventType)
+	request.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	request.Header.Set("X-Webhook-Signature", "sha256="+signWebhook(delivery.Secret, timestamp, delivery.Payload))
+
+	response, err := webhookClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return int32(response.StatusCode), fmt.Errorf("unexpected status %s", response.Status)
+	}
+	return int32(response.StatusCode), nil
+}
+
+// deliverWebhook sends one delivery and records the result. A failed one is retried after a
+// backoff and becomes dead after the last attempt.
+func (service *ProductService) deliverWebhook(ctx context.Context, delivery repository.ClaimWebhookDeliveriesRow) error {
+	responseStatus, deliverErr := postWebhook(ctx, delivery)
+	if deliverErr == nil {
+		return service.productStore.MarkWebhookDelivered(ctx, repository.MarkWebhookDeliveredParams{
+			ID:             delivery.ID,
+			ResponseStatus: responseStatus,
+		})
+	}
+
+	log.Printf("deliver webhook %d error: %v", delivery.ID, deliverErr)
+	deliveryStatus := pb.WebhookDeliveryStatus_pending
+	if delivery.Attempts+1 >= webhookMaxAttempts {
+		deliveryStatus = pb.WebhookDeliveryStatus_dead
+	}
+	return service.productStore.RetryWebhookDelivery(ctx, repository.RetryWebhookDeliveryParams{
+		ID:             delivery.ID,
+		Status:         deliveryStatus.String(),
+		ResponseStatus: responseStatus,
+		LastError:      deliverErr.Error(),
+		NextAttemptAt:  time.Now().Add(webhookBackoff(delivery.Attempts)),
+	})
+}
+
+// deliverWebhooks claims a batch of due deliveries and sends them. Every endpoint gets its
+// deliveries in order, up to webhookConcurrency endpoints at a time, so a slow one only
+// delays its own.
+func (service *ProductService) deliverWebhooks(ctx context.Context) (int, error) {
+	deliveries, err := service.productStore.ClaimWebhookDeliveries(ctx, repository.ClaimWebhookDeliveriesParams{
+		LeaseUntil: time.Now().Add(webhookLease),
+		RowLimit:   webhookBatch,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var endpoints []int64
+	byEndpoint := make(map[int64][]repository.ClaimWebhookDeliveriesRow)
+	for _, delivery := range deliveries {
+		if _, ok := byEndpoint[delivery.SubscriptionID]; !ok {
+			endpoints = append(endpoints, delivery.SubscriptionID)
+		}
+		byEndpoint[delivery.SubscriptionID] = append(byEndpoint[delivery.SubscriptionID], delivery)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	slots := make(chan struct{}, webhookConcurrency)
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(deliveries []repository.ClaimWebhookDeliveriesRow) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			for _, delivery := range deliveries {
+				if err := service.deliverWebhook(ctx, delivery); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					return
+				}
+			}
+		}(byEndpoint[endpoint])
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	return len(deliveries), nil
+}
+
+// DeliverWebhooks sends the due webhook deliveries every interval until ctx is done
+func (service *ProductService) DeliverWebhooks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				delivered, err := service.deliverWebhooks(ctx)
+				if err != nil {
+					log.Println("deliver webhooks error: ", err)
+				}
+				if err != nil || delivered < webhookBatch {
+					break
+				}
+			}
+		}
+	}
+}
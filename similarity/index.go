@@ -0,0 +1,240 @@
+// Package similarity keeps an in-process TF-IDF index over product text and
+// answers "similar items" queries with cosine similarity.
+package similarity
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Document is the text of a product used for similarity
+type Document struct {
+	Name        string
+	Description string
+	Brand       string
+	Category    string
+}
+
+// Vector maps a term to its weighted frequency in a document
+type Vector map[string]float64
+
+// field weights, the product name says much more than its description
+const (
+	nameWeight        = 3
+	brandWeight       = 2
+	categoryWeight    = 2
+	descriptionWeight = 1
+)
+
+// Terms builds the term frequency vector of a document. Words and their
+// character trigrams are both used so that "iphone" still matches "iphone14".
+func Terms(doc Document) Vector {
+	vector := make(Vector)
+	addText(vector, doc.Name, nameWeight)
+	addText(vector, doc.Brand, brandWeight)
+	addText(vector, doc.Category, categoryWeight)
+	addText(vector, doc.Description, descriptionWeight)
+	return vector
+}
+
+func addText(vector Vector, text string, weight float64) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		vector["w:"+word] += weight
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			vector["t:"+string(runes[i:i+3])] += weight / 3
+		}
+	}
+}
+
+// Match is a document similar to the queried one
+type Match struct {
+	ID    int64
+	Score float64
+}
+
+// the candidates of a query come from its rarest terms first, terms in more documents than
+// maxPosting are left out once there are some candidates as they say little about similarity
+const (
+	maxPosting    = 1000
+	maxCandidates = 2000
+)
+
+// Index is safe for concurrent use
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[int64]Vector
+	norms    map[int64]float64
+	postings map[string]map[int64]struct{}
+	// number of documents when the norms were all computed, they are computed again once
+	// the index has doubled or halved since as the idf of every term has moved
+	weighedLen int
+}
+
+// NewIndex creates an empty Index
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[int64]Vector),
+		norms:    make(map[int64]float64),
+		postings: make(map[string]map[int64]struct{}),
+	}
+}
+
+// Len returns the number of indexed documents
+func (index *Index) Len() int {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+	return len(index.docs)
+}
+
+// Put adds or replaces the vector of a document
+func (index *Index) Put(id int64, vector Vector) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	index.remove(id)
+	index.docs[id] = vector
+	for term := range vector {
+		ids, ok := index.postings[term]
+		if !ok {
+			ids = make(map[int64]struct{})
+			index.postings[term] = ids
+		}
+		ids[id] = struct{}{}
+	}
+	index.norms[id] = norm(index.weigh(vector))
+	index.reweighIfStale()
+}
+
+// Remove deletes a document from the index
+func (index *Index) Remove(id int64) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	index.remove(id)
+}
+
+func (index *Index) remove(id int64) {
+	vector, ok := index.docs[id]
+	if !ok {
+		return
+	}
+	for term := range vector {
+		delete(index.postings[term], id)
+		if len(index.postings[term]) == 0 {
+			delete(index.postings, term)
+		}
+	}
+	delete(index.docs, id)
+	delete(index.norms, id)
+	index.reweighIfStale()
+}
+
+func (index *Index) reweighIfStale() {
+	if len(index.docs) <= 2*index.weighedLen && 2*len(index.docs) >= index.weighedLen {
+		return
+	}
+	for id, vector := range index.docs {
+		index.norms[id] = norm(index.weigh(vector))
+	}
+	index.weighedLen = len(index.docs)
+}
+
+// Similar returns up to limit documents ranked by TF-IDF cosine similarity to id
+func (index *Index) Similar(id int64, limit int) []Match {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	query, ok := index.docs[id]
+	if !ok || limit <= 0 {
+		return nil
+	}
+	queryWeights := index.weigh(query)
+	queryNorm := norm(queryWeights)
+	if queryNorm == 0 {
+		return nil
+	}
+
+	matches := make([]Match, 0)
+	for other := range index.candidates(id, query) {
+		otherNorm := index.norms[other]
+		if otherNorm == 0 {
+			continue
+		}
+		var dot float64
+		for term, tf := range index.docs[other] {
+			if weight, ok := queryWeights[term]; ok {
+				dot += weight * tf * index.idf(term)
+			}
+		}
+		matches = append(matches, Match{ID: other, Score: dot / (queryNorm * otherNorm)})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score == matches[j].Score {
+			return matches[i].ID > matches[j].ID
+		}
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// candidates collects the documents sharing a term with the query, from the rarest terms
+// to the most common ones until there are maxCandidates
+func (index *Index) candidates(id int64, query Vector) map[int64]struct{} {
+	terms := make([]string, 0, len(query))
+	for term := range query {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if len(index.postings[terms[i]]) == len(index.postings[terms[j]]) {
+			return terms[i] < terms[j]
+		}
+		return len(index.postings[terms[i]]) < len(index.postings[terms[j]])
+	})
+
+	candidates := make(map[int64]struct{})
+	for _, term := range terms {
+		posting := index.postings[term]
+		if len(posting) > maxPosting && len(candidates) > 0 {
+			break
+		}
+		for other := range posting {
+			if other == id {
+				continue
+			}
+			candidates[other] = struct{}{}
+			if len(candidates) >= maxCandidates {
+				return candidates
+			}
+		}
+	}
+	return candidates
+}
+
+func (index *Index) idf(term string) float64 {
+	return math.Log(1 + float64(len(index.docs))/float64(1+len(index.postings[term])))
+}
+
+func (index *Index) weigh(vector Vector) Vector {
+	weights := make(Vector, len(vector))
+	for term, tf := range vector {
+		weights[term] = tf * index.idf(term)
+	}
+	return weights
+}
+
+func norm(vector Vector) float64 {
+	var sum float64
+	for _, weight := range vector {
+		sum += weight * weight
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,180 @@
+package similarity
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  Document
+		term string
+		want float64
+	}{
+		{"name word", Document{Name: "iPhone"}, "w:iphone", nameWeight},
+		{"brand word", Document{Brand: "Apple"}, "w:apple", brandWeight},
+		{"category word", Document{Category: "Phone"}, "w:phone", categoryWeight},
+		{"description word", Document{Description: "fast"}, "w:fast", descriptionWeight},
+		{"fields add up", Document{Name: "apple", Brand: "apple"}, "w:apple", nameWeight + brandWeight},
+		{"punctuation splits words", Document{Name: "usb-c"}, "w:usb", nameWeight},
+		{"trigram at word start", Document{Name: "ab"}, "t: ab", float64(nameWeight) / 3},
+		{"trigram at word end", Document{Name: "ab"}, "t:ab ", float64(nameWeight) / 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Terms(test.doc)[test.term]
+			if math.Abs(got-test.want) > 1e-9 {
+				t.Errorf("Terms()[%q] = %v, want %v", test.term, got, test.want)
+			}
+		})
+	}
+}
+
+func catalog() *Index {
+	index := NewIndex()
+	index.Put(1, Terms(Document{Name: "iPhone 14 Pro", Brand: "Apple", Category: "Phone"}))
+	index.Put(2, Terms(Document{Name: "iPhone 14", Brand: "Apple", Category: "Phone"}))
+	index.Put(3, Terms(Document{Name: "Galaxy S23", Brand: "Samsung", Category: "Phone"}))
+	index.Put(4, Terms(Document{Name: "Cotton T-Shirt", Brand: "Uniqlo", Category: "Fashion"}))
+	return index
+}
+
+func ids(matches []Match) []int64 {
+	result := make([]int64, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, match.ID)
+	}
+	return result
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSimilar(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    int64
+		limit int
+		want  []int64
+	}{
+		{"closest first", 1, 10, []int64{2, 3}},
+		{"limit", 1, 1, []int64{2}},
+		{"nothing in common", 4, 10, []int64{}},
+		{"unknown document", 99, 10, nil},
+		{"no limit", 1, 0, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := catalog().Similar(test.id, test.limit)
+			if !equalIDs(ids(got), test.want) {
+				t.Errorf("Similar(%d, %d) = %v, want %v", test.id, test.limit, ids(got), test.want)
+			}
+		})
+	}
+}
+
+func TestSimilarScore(t *testing.T) {
+	index := NewIndex()
+	vector := Terms(Document{Name: "desk lamp"})
+	index.Put(1, vector)
+	index.Put(2, vector)
+	index.Put(3, Terms(Document{Name: "office chair"}))
+
+	matches := index.Similar(1, 10)
+	if len(matches) != 1 || matches[0].ID != 2 {
+		t.Fatalf("Similar(1) = %v, want only 2", ids(matches))
+	}
+	if math.Abs(matches[0].Score-1) > 1e-9 {
+		t.Errorf("score of identical documents = %v, want 1", matches[0].Score)
+	}
+}
+
+func TestIndexMaintenance(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(index *Index)
+		wantLen int
+		query   int64
+		want    []int64
+	}{
+		{
+			name:    "remove drops the document from results",
+			change:  func(index *Index) { index.Remove(2) },
+			wantLen: 3,
+			query:   1,
+			want:    []int64{3},
+		},
+		{
+			name:    "remove unknown document",
+			change:  func(index *Index) { index.Remove(99) },
+			wantLen: 4,
+			query:   1,
+			want:    []int64{2, 3},
+		},
+		{
+			name: "put replaces the vector",
+			change: func(index *Index) {
+				index.Put(2, Terms(Document{Name: "Wool Sweater", Brand: "Uniqlo", Category: "Fashion"}))
+			},
+			wantLen: 4,
+			query:   1,
+			want:    []int64{3},
+		},
+		{
+			name: "replaced vector matches its new neighbours",
+			change: func(index *Index) {
+				index.Put(2, Terms(Document{Name: "Cotton Shirt", Brand: "Uniqlo", Category: "Fashion"}))
+			},
+			wantLen: 4,
+			query:   4,
+			want:    []int64{2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			index := catalog()
+			test.change(index)
+			if index.Len() != test.wantLen {
+				t.Errorf("Len() = %d, want %d", index.Len(), test.wantLen)
+			}
+			got := index.Similar(test.query, 10)
+			if !equalIDs(ids(got), test.want) {
+				t.Errorf("Similar(%d) = %v, want %v", test.query, ids(got), test.want)
+			}
+		})
+	}
+}
+
+func TestRemoveCleansPostings(t *testing.T) {
+	index := catalog()
+	for _, id := range []int64{1, 2, 3, 4} {
+		index.Remove(id)
+	}
+	if len(index.postings) != 0 || len(index.norms) != 0 {
+		t.Errorf("empty index keeps %d postings and %d norms", len(index.postings), len(index.norms))
+	}
+}
+
+func TestNormsFollowIndexGrowth(t *testing.T) {
+	index := NewIndex()
+	for id := int64(1); id <= 64; id++ {
+		index.Put(id, Terms(Document{Name: "lamp " + strconv.FormatInt(id, 10)}))
+	}
+	for id, vector := range index.docs {
+		want := norm(index.weigh(vector))
+		if math.Abs(index.norms[id]-want)/want > 0.5 {
+			t.Errorf("norm of %d = %v, want about %v", id, index.norms[id], want)
+		}
+	}
+}
@@ -0,0 +1,523 @@
+// Package suggest keeps an in-process prefix index over product names, brands and
+// categories and answers search-as-you-type queries ranked by popularity.
+package suggest
+
+import (
+	"container/heap"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Kind tells what a suggestion names
+type Kind int
+
+// suggestion kinds
+const (
+	Product Kind = iota
+	Brand
+	Category
+)
+
+// Item is the text of a product used for suggestions
+type Item struct {
+	ID         int64
+	Name       string
+	Brand      string
+	CategoryID int64
+}
+
+// Suggestion is a product, brand or category matching the typed prefix. ID is the
+// product or category id, brands have none.
+type Suggestion struct {
+	Kind       Kind
+	ID         int64
+	Text       string
+	Popularity float64
+}
+
+// Fold lowercases text, strips the diacritics, đ included, and keeps letters
+// and digits separated by single spaces, so "Điện Thoại" folds to "dien thoai"
+func Fold(text string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range norm.NFD.String(text) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r == 'đ' || r == 'Đ':
+			r = 'd'
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+type entryKey struct {
+	kind  Kind
+	id    int64
+	brand string
+}
+
+type entry struct {
+	key  entryKey
+	text string
+	// products of a brand or category, a product entry has none and is its own only member
+	members map[int64]struct{}
+	// popularity of the members added up, kept as they change so Suggest doesn't sum them
+	popularity float64
+}
+
+// prefixKey points from one searchable suffix of an entry text to the entry,
+// every word start is a suffix so "thoai" finds "Điện thoại"
+type prefixKey struct {
+	term  string
+	entry *entry
+}
+
+// Index is safe for concurrent use.
+//
+// Memory grows with the catalogue, about 600 bytes per product with a six word name,
+// e.g. 30MiB for 50k products: a 24 byte key per word start, the folded text, the entry,
+// the product and its place in its brand and category. The memory limit of the service
+// in deploy/service.yaml has to leave room for it.
+type Index struct {
+	mu         sync.RWMutex
+	items      map[int64]Item
+	popularity map[int64]float64
+	entries    map[entryKey]*entry
+	keys       sortedKeys
+}
+
+// NewIndex creates an empty Index
+func NewIndex() *Index {
+	return &Index{
+		items:      make(map[int64]Item),
+		popularity: make(map[int64]float64),
+		entries:    make(map[entryKey]*entry),
+	}
+}
+
+// Len returns the number of indexed products
+func (index *Index) Len() int {
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+	return len(index.items)
+}
+
+// PutProduct adds or replaces a product, its brand becomes suggested as well
+func (index *Index) PutProduct(item Item) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	if existing, ok := index.items[item.ID]; ok && existing == item {
+		return
+	}
+	index.putProduct(item, index.keys.insert)
+}
+
+// PutProducts adds or replaces many products at once, much faster than one by one
+// when the index is being filled
+func (index *Index) PutProducts(items []Item) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	// remove replaced products while the keys are still sorted
+	latest := make(map[int64]Item, len(items))
+	for _, item := range items {
+		index.removeProduct(item.ID)
+		latest[item.ID] = item
+	}
+	keys := index.keys.all()
+	for _, item := range latest {
+		index.putProduct(item, func(key prefixKey) {
+			keys = append(keys, key)
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return less(keys[i], keys[j])
+	})
+	index.keys.build(keys)
+}
+
+func (index *Index) putProduct(item Item, addKey func(key prefixKey)) {
+	index.removeProduct(item.ID)
+	index.items[item.ID] = item
+	index.addEntry(entryKey{kind: Product, id: item.ID}, item.Name, item.ID, addKey)
+	if brand := Fold(item.Brand); brand != "" {
+		index.addEntry(entryKey{kind: Brand, brand: brand}, item.Brand, item.ID, addKey)
+	}
+	if category, ok := index.entries[entryKey{kind: Category, id: item.CategoryID}]; ok {
+		category.members[item.ID] = struct{}{}
+		category.popularity += index.popularity[item.ID]
+	}
+}
+
+// RemoveProduct deletes a product, its brand goes away with its last product
+func (index *Index) RemoveProduct(id int64) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	index.removeProduct(id)
+	delete(index.popularity, id)
+}
+
+func (index *Index) removeProduct(id int64) {
+	item, ok := index.items[id]
+	if !ok {
+		return
+	}
+	index.removeMember(entryKey{kind: Product, id: id}, id)
+	if brand := Fold(item.Brand); brand != "" {
+		index.removeMember(entryKey{kind: Brand, brand: brand}, id)
+	}
+	if category, ok := index.entries[entryKey{kind: Category, id: item.CategoryID}]; ok {
+		if _, ok := category.members[id]; ok {
+			delete(category.members, id)
+			category.popularity -= index.popularity[id]
+		}
+	}
+	delete(index.items, id)
+}
+
+// SetCategory adds or renames a category
+func (index *Index) SetCategory(id int64, name string) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	key := entryKey{kind: Category, id: id}
+	category, ok := index.entries[key]
+	if ok && category.text == name {
+		return
+	}
+	if ok {
+		index.removeKeys(category)
+		category.text = name
+	} else {
+		category = &entry{key: key, text: name, members: make(map[int64]struct{})}
+		for _, item := range index.items {
+			if item.CategoryID == id {
+				category.members[item.ID] = struct{}{}
+				category.popularity += index.popularity[item.ID]
+			}
+		}
+		index.entries[key] = category
+	}
+	index.addKeys(category, index.keys.insert)
+}
+
+// SetPopularity sets how popular a product is, brands and categories add up their products
+func (index *Index) SetPopularity(productID int64, popularity float64) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	index.addPopularity(productID, popularity-index.popularity[productID])
+}
+
+// AddPopularity makes a product more popular
+func (index *Index) AddPopularity(productID int64, popularity float64) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	if _, ok := index.items[productID]; ok {
+		index.addPopularity(productID, popularity)
+	}
+}
+
+func (index *Index) addPopularity(productID int64, delta float64) {
+	index.popularity[productID] += delta
+	item, ok := index.items[productID]
+	if !ok {
+		return
+	}
+	keys := []entryKey{{kind: Product, id: item.ID}, {kind: Category, id: item.CategoryID}}
+	if brand := Fold(item.Brand); brand != "" {
+		keys = append(keys, entryKey{kind: Brand, brand: brand})
+	}
+	for _, key := range keys {
+		if entry, ok := index.entries[key]; ok {
+			entry.popularity += delta
+		}
+	}
+}
+
+// maxCandidates bounds the entries a prefix is matched against, so a one letter prefix
+// doesn't rank the whole catalogue on every keystroke. A broader prefix is ranked among
+// the first maxCandidates entries matching it in alphabetical order.
+const maxCandidates = 2000
+
+// Suggest returns up to limit entries with a word starting with prefix, most popular first
+func (index *Index) Suggest(prefix string, limit int) []Suggestion {
+	prefix = Fold(prefix)
+	if prefix == "" || limit <= 0 {
+		return nil
+	}
+
+	index.mu.RLock()
+	defer index.mu.RUnlock()
+
+	// the best limit suggestions so far, the worst of them on top
+	top := make(suggestionHeap, 0, limit)
+	seen := make(map[*entry]struct{})
+	index.keys.ascend(prefix, func(key prefixKey) bool {
+		if !strings.HasPrefix(key.term, prefix) || len(seen) == maxCandidates {
+			return false
+		}
+		if _, ok := seen[key.entry]; ok {
+			return true
+		}
+		seen[key.entry] = struct{}{}
+		suggestion := Suggestion{
+			Kind:       key.entry.key.kind,
+			ID:         key.entry.key.id,
+			Text:       key.entry.text,
+			Popularity: key.entry.popularity,
+		}
+		if len(top) < limit {
+			heap.Push(&top, suggestion)
+		} else if ranksBefore(suggestion, top[0]) {
+			top[0] = suggestion
+			heap.Fix(&top, 0)
+		}
+		return true
+	})
+
+	suggestions := make([]Suggestion, len(top))
+	for i := len(suggestions) - 1; i >= 0; i-- {
+		suggestions[i] = heap.Pop(&top).(Suggestion)
+	}
+	return suggestions
+}
+
+// ranksBefore orders suggestions by popularity, then categories before brands before
+// products, then by text
+func ranksBefore(a, b Suggestion) bool {
+	if a.Popularity != b.Popularity {
+		return a.Popularity > b.Popularity
+	}
+	if a.Kind != b.Kind {
+		return a.Kind > b.Kind
+	}
+	return a.Text < b.Text
+}
+
+// suggestionHeap is a heap.Interface keeping the worst ranked suggestion on top
+type suggestionHeap []Suggestion
+
+func (h suggestionHeap) Len() int            { return len(h) }
+func (h suggestionHeap) Less(i, j int) bool  { return ranksBefore(h[j], h[i]) }
+func (h suggestionHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *suggestionHeap) Push(x interface{}) { *h = append(*h, x.(Suggestion)) }
+
+func (h *suggestionHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+func (index *Index) addEntry(key entryKey, text string, member int64, addKey func(key prefixKey)) {
+	existing, ok := index.entries[key]
+	if !ok {
+		existing = &entry{key: key, text: text}
+		if key.kind != Product {
+			existing.members = make(map[int64]struct{})
+		}
+		index.entries[key] = existing
+		index.addKeys(existing, addKey)
+	}
+	if existing.members == nil {
+		existing.popularity = index.popularity[member]
+		return
+	}
+	if _, ok := existing.members[member]; !ok {
+		existing.members[member] = struct{}{}
+		existing.popularity += index.popularity[member]
+	}
+}
+
+func (index *Index) removeMember(key entryKey, member int64) {
+	existing, ok := index.entries[key]
+	if !ok {
+		return
+	}
+	if existing.members != nil {
+		if _, ok := existing.members[member]; !ok {
+			return
+		}
+		delete(existing.members, member)
+		existing.popularity -= index.popularity[member]
+		if len(existing.members) > 0 {
+			return
+		}
+	}
+	index.removeKeys(existing)
+	delete(index.entries, key)
+}
+
+// suffixes returns the folded text from every word start, they share the folded text
+func suffixes(text string) []string {
+	folded := Fold(text)
+	if folded == "" {
+		return nil
+	}
+	terms := []string{folded}
+	for i := 0; i < len(folded); i++ {
+		if folded[i] == ' ' {
+			terms = append(terms, folded[i+1:])
+		}
+	}
+	return terms
+}
+
+func (index *Index) addKeys(e *entry, addKey func(key prefixKey)) {
+	for _, term := range suffixes(e.text) {
+		addKey(prefixKey{term: term, entry: e})
+	}
+}
+
+func (index *Index) removeKeys(e *entry) {
+	for _, term := range suffixes(e.text) {
+		index.keys.remove(prefixKey{term: term, entry: e})
+	}
+}
+
+func less(a, b prefixKey) bool {
+	if a.term != b.term {
+		return a.term < b.term
+	}
+	if a.entry.key.kind != b.entry.key.kind {
+		return a.entry.key.kind < b.entry.key.kind
+	}
+	if a.entry.key.id != b.entry.key.id {
+		return a.entry.key.id < b.entry.key.id
+	}
+	return a.entry.key.brand < b.entry.key.brand
+}
+
+// blockSize bounds the keys shifted by an insert or remove
+const blockSize = 512
+
+// sortedKeys keeps the prefix keys sorted in blocks of up to 2*blockSize keys, so an update
+// only shifts the keys of one block instead of the whole catalogue
+type sortedKeys struct {
+	blocks [][]prefixKey
+}
+
+// locate returns the block that holds key or would hold it, and the position in that block
+func (keys *sortedKeys) locate(key prefixKey) (int, int) {
+	b := sort.Search(len(keys.blocks), func(b int) bool {
+		block := keys.blocks[b]
+		return !less(block[len(block)-1], key)
+	})
+	if b == len(keys.blocks) {
+		b--
+	}
+	block := keys.blocks[b]
+	return b, sort.Search(len(block), func(i int) bool {
+		return !less(block[i], key)
+	})
+}
+
+func (keys *sortedKeys) insert(key prefixKey) {
+	if len(keys.blocks) == 0 {
+		keys.blocks = [][]prefixKey{{key}}
+		return
+	}
+	b, i := keys.locate(key)
+	block := keys.blocks[b]
+	if i < len(block) && block[i] == key {
+		return
+	}
+	block = append(block, prefixKey{})
+	copy(block[i+1:], block[i:])
+	block[i] = key
+	if len(block) < 2*blockSize {
+		keys.blocks[b] = block
+		return
+	}
+	// split the full block in halves
+	second := append(make([]prefixKey, 0, blockSize), block[blockSize:]...)
+	keys.blocks = append(keys.blocks, nil)
+	copy(keys.blocks[b+2:], keys.blocks[b+1:])
+	for i := blockSize; i < len(block); i++ {
+		block[i] = prefixKey{}
+	}
+	keys.blocks[b] = block[:blockSize]
+	keys.blocks[b+1] = second
+}
+
+func (keys *sortedKeys) remove(key prefixKey) {
+	if len(keys.blocks) == 0 {
+		return
+	}
+	b, i := keys.locate(key)
+	block := keys.blocks[b]
+	if i == len(block) || block[i] != key {
+		return
+	}
+	block = append(block[:i], block[i+1:]...)
+	block[:cap(block)][len(block)] = prefixKey{}
+	if len(block) == 0 {
+		keys.blocks = append(keys.blocks[:b], keys.blocks[b+1:]...)
+		return
+	}
+	keys.blocks[b] = block
+}
+
+// ascend calls visit with the keys from the first one with a term at or after from,
+// until visit returns false
+func (keys *sortedKeys) ascend(from string, visit func(key prefixKey) bool) {
+	b := sort.Search(len(keys.blocks), func(b int) bool {
+		block := keys.blocks[b]
+		return block[len(block)-1].term >= from
+	})
+	for ; b < len(keys.blocks); b++ {
+		block := keys.blocks[b]
+		i := sort.Search(len(block), func(i int) bool {
+			return block[i].term >= from
+		})
+		for ; i < len(block); i++ {
+			if !visit(block[i]) {
+				return
+			}
+		}
+	}
+}
+
+// all returns the keys in order
+func (keys *sortedKeys) all() []prefixKey {
+	var result []prefixKey
+	for _, block := range keys.blocks {
+		result = append(result, block...)
+	}
+	return result
+}
+
+// build replaces the keys with sorted ones, dropping duplicates
+func (keys *sortedKeys) build(sorted []prefixKey) {
+	keys.blocks = keys.blocks[:0]
+	var block []prefixKey
+	for i, key := range sorted {
+		if i > 0 && key == sorted[i-1] {
+			continue
+		}
+		if len(block) == blockSize {
+			keys.blocks = append(keys.blocks, block)
+			block = nil
+		}
+		if block == nil {
+			block = make([]prefixKey, 0, blockSize)
+		}
+		block = append(block, key)
+	}
+	if len(block) > 0 {
+		keys.blocks = append(keys.blocks, block)
+	}
+}